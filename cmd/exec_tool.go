@@ -18,7 +18,7 @@ type ExecCmd struct {
 	Name       string `short:"n" long:"name" positional-arg-name:"tool" description:"Tool name (service_method)" required:"yes"`
 	Inline     string `short:"i" long:"input" description:"Inline JSON arguments (object)"`
 	File       string `short:"f" long:"file" description:"Path to JSON file with arguments (use - for stdin)"`
-	TimeoutSec int    `long:"timeout" description:"Seconds to wait for completion" default:"120"`
+	TimeoutSec int    `long:"timeout" description:"Seconds to wait for completion (default: tools.timeoutSec from config)"`
 	JSON       bool   `long:"json" description:"Print result as JSON"`
 }
 
@@ -66,10 +66,7 @@ func (c *ExecCmd) Execute(_ []string) error {
 	}
 
 	ctx := context.Background()
-	timeout := time.Duration(c.TimeoutSec) * time.Second
-	if timeout == 0 {
-		timeout = 120 * time.Second
-	}
+	timeout := time.Duration(c.TimeoutSec) * time.Second // zero – configured tool timeout
 	canonical := tool.Canonical(c.Name)
 
 	out, err := svc.ExecuteTool(ctx, canonical, args, timeout)
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"reflect"
+
+	"github.com/viant/fluxor-mcp/mcp/tool"
+	"github.com/viant/fluxor/model/types"
+)
+
+// toolAnnotations returns the behaviour hints of a tool identified by its
+// canonical name: the hints attached to the service instance (see annotate)
+// and those of the service itself (tool.Annotator, e.g. imported tools),
+// overridden by matching tools rules in declaration order. Only explicit
+// metadata is used; nothing is inferred from the method name.
+func (s *Service) toolAnnotations(name string, service types.Service, method string) *tool.Annotations {
+	ret := s.serviceAnnotations(service)
+	if annotator, ok := service.(tool.Annotator); ok {
+		ret = ret.Merge(annotator.Annotations(method))
+	}
+	for _, rule := range toolRules(s.config.Tools, name) {
+		ret = ret.Merge(rule.Annotations)
+	}
+	return ret
+}
+
+// annotate attaches behaviour hints to every method of a service instance
+// without wrapping it. Only pointer instances can carry hints.
+func (s *Service) annotate(service types.Service, annotations *tool.Annotations) {
+	if annotations == nil || !isPointer(service) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.hints == nil {
+		s.hints = make(map[types.Service]*tool.Annotations)
+	}
+	s.hints[service] = annotations
+}
+
+// serviceAnnotations returns the hints attached to a service instance.
+func (s *Service) serviceAnnotations(service types.Service) *tool.Annotations {
+	if !isPointer(service) {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.hints[service]
+}
+
+func isPointer(service types.Service) bool {
+	return service != nil && reflect.TypeOf(service).Kind() == reflect.Pointer
+}
@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/fluxor-mcp/internal/conv"
+	"github.com/viant/fluxor-mcp/mcp/config"
+	"github.com/viant/fluxor-mcp/mcp/tool"
+	"github.com/viant/fluxor/model/types"
+	mcpschema "github.com/viant/mcp-protocol/schema"
+)
+
+func TestService_ToolAnnotations(t *testing.T) {
+	ctx := context.Background()
+	svc, err := New(ctx, WithConfig(&config.Config{
+		Builtins: []string{"printer", "system/exec"},
+		Tools: &config.Tools{Rules: []*config.ToolRule{
+			{Pattern: "printer", Annotations: &tool.Annotations{Idempotent: conv.Pointer(true)}},
+		}},
+	}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	entry, err := svc.LookupTool("printer-print")
+	if !assert.NoError(t, err) {
+		return
+	}
+	expect := &mcpschema.ToolAnnotations{ReadOnlyHint: conv.Pointer(true), OpenWorldHint: conv.Pointer(false), IdempotentHint: conv.Pointer(true)}
+	assert.EqualValues(t, expect, entry.Metadata.Annotations, "built-in hints with rule override")
+
+	// built-in instances are registered as they are, keeping their own type
+	// and optional interfaces
+	registered := svc.WorkflowService().Actions().Lookup("system/exec")
+	assert.IsType(t, builtinFactories["system/exec"](), registered)
+	exec := svc.toolAnnotations("system/exec/execute", registered, "execute")
+	assert.EqualValues(t, &mcpschema.ToolAnnotations{ReadOnlyHint: conv.Pointer(false), DestructiveHint: conv.Pointer(true), OpenWorldHint: conv.Pointer(true)}, exec.Schema())
+
+	imported := svc.toolAnnotations("printer/print", &namedService{name: "printer"}, "print")
+	assert.EqualValues(t, &mcpschema.ToolAnnotations{IdempotentHint: conv.Pointer(true)}, imported.Schema(), "rule hints only for a service named like a built-in one")
+
+	annotator := &annotatedService{namedService: namedService{name: "custom"}}
+	svc.annotate(annotator, &tool.Annotations{ReadOnly: conv.Pointer(true), OpenWorld: conv.Pointer(false)})
+	custom := svc.toolAnnotations("custom/run", annotator, "run")
+	assert.EqualValues(t, &mcpschema.ToolAnnotations{ReadOnlyHint: conv.Pointer(true), OpenWorldHint: conv.Pointer(true)}, custom.Schema(), "attached hints overridden by the service's own")
+	assert.Nil(t, svc.toolAnnotations("value/run", valueService{}, "run"), "value services carry no attached hints")
+}
+
+// annotatedService is a service implementing tool.Annotator.
+type annotatedService struct {
+	namedService
+}
+
+func (s *annotatedService) Annotations(string) *tool.Annotations {
+	return &tool.Annotations{OpenWorld: conv.Pointer(true)}
+}
+
+// valueService is a non-pointer service with a non-comparable field.
+type valueService struct {
+	tags []string
+}
+
+func (s valueService) Name() string { return "value" }
+
+func (s valueService) Methods() types.Signatures { return nil }
+
+func (s valueService) Method(name string) (types.Executable, error) {
+	return nil, types.NewMethodNotFoundError(name)
+}
+
+// namedService is a service without methods.
+type namedService struct {
+	name string
+}
+
+func (s *namedService) Name() string { return s.name }
+
+func (s *namedService) Methods() types.Signatures { return nil }
+
+func (s *namedService) Method(name string) (types.Executable, error) {
+	return nil, types.NewMethodNotFoundError(name)
+}
@@ -0,0 +1,5 @@
+// Package auth forwards the credentials of callers connected to the served
+// MCP endpoint to imported MCP servers. A caller token is either passed
+// through unchanged or exchanged (RFC 8693) for a token scoped to the
+// upstream server.
+package auth
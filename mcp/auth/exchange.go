@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token exchange (RFC 8693) constants.
+const (
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	TokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+// expiryMargin is subtracted from exchanged token lifetimes so that a token
+// is not used just before it expires.
+const expiryMargin = 30 * time.Second
+
+// ExchangeFunc exchanges a caller token for a token accepted by the named
+// upstream server.
+type ExchangeFunc func(ctx context.Context, upstream, token string) (string, error)
+
+// ExchangeOptions configures an RFC 8693 token exchange endpoint.
+type ExchangeOptions struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Audience     string
+	Scope        string
+}
+
+// Exchanger exchanges caller tokens at a token endpoint and caches the
+// results until they expire.
+type Exchanger struct {
+	options ExchangeOptions
+	client  *http.Client
+	mu      sync.Mutex
+	tokens  map[string]*exchanged
+}
+
+type exchanged struct {
+	token   string
+	expires time.Time
+}
+
+// NewExchanger creates an exchanger; a nil client uses http.DefaultClient.
+func NewExchanger(options ExchangeOptions, client *http.Client) *Exchanger {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Exchanger{options: options, client: client, tokens: make(map[string]*exchanged)}
+}
+
+// Exchange returns a token scoped to the configured audience for the caller
+// token. It matches ExchangeFunc, the upstream name is not used.
+func (e *Exchanger) Exchange(ctx context.Context, _ string, token string) (string, error) {
+	key := cacheKey(token)
+	e.mu.Lock()
+	cached, ok := e.tokens[key]
+	e.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.token, nil
+	}
+
+	form := url.Values{
+		"grant_type":         {GrantTypeTokenExchange},
+		"subject_token":      {token},
+		"subject_token_type": {TokenTypeAccessToken},
+	}
+	if e.options.Audience != "" {
+		form.Set("audience", e.options.Audience)
+	}
+	if e.options.Scope != "" {
+		form.Set("scope", e.options.Scope)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, e.options.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if e.options.ClientID != "" {
+		request.SetBasicAuth(url.QueryEscape(e.options.ClientID), url.QueryEscape(e.options.ClientSecret))
+	}
+	response, err := e.client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("token exchange: %w", err)
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("token exchange: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token exchange: status %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+	result := struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}{}
+	if err = json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("token exchange: %w", err)
+	}
+	if result.AccessToken == "" {
+		return "", fmt.Errorf("token exchange: no access_token in response")
+	}
+	if lifetime := time.Duration(result.ExpiresIn)*time.Second - expiryMargin; lifetime > 0 {
+		now := time.Now()
+		e.mu.Lock()
+		for k, v := range e.tokens {
+			if now.After(v.expires) {
+				delete(e.tokens, k)
+			}
+		}
+		e.tokens[key] = &exchanged{token: result.AccessToken, expires: now.Add(lifetime)}
+		e.mu.Unlock()
+	}
+	return result.AccessToken, nil
+}
+
+// cacheKey avoids keeping caller tokens as map keys.
+func cacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
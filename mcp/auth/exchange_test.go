@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchanger_Exchange(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_ = r.ParseForm()
+		clientID, secret, _ := r.BasicAuth()
+		switch {
+		case r.Form.Get("grant_type") != GrantTypeTokenExchange, clientID != "gateway", secret != "s3cret":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_request"}`))
+		case r.Form.Get("subject_token") == "revoked":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+		default:
+			_, _ = fmt.Fprintf(w, `{"access_token":"%s@%s","expires_in":3600}`, r.Form.Get("subject_token"), r.Form.Get("audience"))
+		}
+	}))
+	defer server.Close()
+
+	exchanger := NewExchanger(ExchangeOptions{TokenURL: server.URL, ClientID: "gateway", ClientSecret: "s3cret", Audience: "analytics"}, server.Client())
+	ctx := context.Background()
+
+	token, err := exchanger.Exchange(ctx, "analytics", "alice")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "alice@analytics", token)
+	token, err = exchanger.Exchange(ctx, "analytics", "alice")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "alice@analytics", token)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls), "exchanged token cached")
+
+	token, err = exchanger.Exchange(ctx, "analytics", "bob")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "bob@analytics", token)
+
+	_, err = exchanger.Exchange(ctx, "analytics", "revoked")
+	assert.ErrorContains(t, err, "status 401")
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+)
+
+// VerifyFunc verifies a caller token and returns the caller identity, e.g.
+// the "sub" claim of a JWT whose signature and expiry it checked.
+type VerifyFunc func(ctx context.Context, token string) (string, error)
+
+// NewPrincipal builds a caller from a bearer token, as received in the
+// Authorization header or request _meta. The token is not verified, so the
+// subject is left empty; see Verify.
+func NewPrincipal(token string) *mcontext.Principal {
+	token = strings.TrimSpace(token)
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+	if token == "" {
+		return nil
+	}
+	return &mcontext.Principal{Token: token}
+}
+
+// Verify sets the subject of caller to the identity verify returns for its
+// token; a token failing verification leaves the subject empty.
+func Verify(ctx context.Context, caller *mcontext.Principal, verify VerifyFunc) {
+	if caller == nil || verify == nil {
+		return
+	}
+	if subject, err := verify(ctx, caller.Token); err == nil {
+		caller.Subject = subject
+	}
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+)
+
+func TestNewPrincipal(t *testing.T) {
+	jwt := "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"alice"}`)) + ".sig"
+	testCases := []struct {
+		description string
+		token       string
+		expect      *mcontext.Principal
+	}{
+		{description: "empty", token: " "},
+		{description: "opaque", token: "abc", expect: &mcontext.Principal{Token: "abc"}},
+		{description: "bearer header", token: "Bearer abc", expect: &mcontext.Principal{Token: "abc"}},
+		{description: "unverified jwt", token: "bearer " + jwt, expect: &mcontext.Principal{Token: jwt}},
+	}
+	for _, testCase := range testCases {
+		assert.EqualValues(t, testCase.expect, NewPrincipal(testCase.token), testCase.description)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	verify := func(ctx context.Context, token string) (string, error) {
+		if token != "valid" {
+			return "", errors.New("invalid token")
+		}
+		return "alice", nil
+	}
+	testCases := []struct {
+		description string
+		token       string
+		verify      VerifyFunc
+		expect      string
+	}{
+		{description: "verified", token: "valid", verify: verify, expect: "alice"},
+		{description: "rejected", token: "forged", verify: verify},
+		{description: "no verifier", token: "valid"},
+	}
+	for _, testCase := range testCases {
+		caller := &mcontext.Principal{Token: testCase.token}
+		Verify(ctx, caller, testCase.verify)
+		assert.EqualValues(t, testCase.expect, caller.Subject, testCase.description)
+	}
+}
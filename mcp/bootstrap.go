@@ -3,10 +3,13 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/viant/fluxor"
 	"github.com/viant/fluxor-mcp/mcp/clientaction"
 	"github.com/viant/fluxor-mcp/mcp/config"
+	"github.com/viant/fluxor-mcp/mcp/job"
+	"github.com/viant/fluxor-mcp/mcp/tool"
 )
 
 // init is the main bootstrap routine invoked by proxy.go once all options
@@ -22,11 +25,17 @@ func (s *Service) init(ctx context.Context) error {
 
 	s.initWorkflowService()
 
+	// Publish configured workflow definitions as tools.
+	if err := s.registerWorkflowTools(ctx); err != nil {
+		return fmt.Errorf("register workflows: %w", err)
+	}
+
 	// Register external Server tools – turn them into dynamic Fluxor services so
 	// they can be consumed like native actions.
 	if err := s.registerExternalActions(ctx); err != nil {
 		return fmt.Errorf("register externals: %w", err)
 	}
+	s.indexToolNames()
 
 	// Auto-start runtime so that callers get a ready-to-use instance without
 	// requiring an additional Start() call.
@@ -43,6 +52,15 @@ func (s *Service) initDefaults() {
 	if len(s.config.Builtins) == 0 { //add all buildin fluxor action
 		s.config.Builtins = append(s.config.Builtins, "*")
 	}
+	if s.jobs == nil {
+		s.jobs = job.NewMemoryStore(0)
+	}
+	if tools := s.config.Tools; tools != nil && tools.Naming != nil {
+		s.naming = tool.NewNaming(tools.Naming.MaxLength)
+	}
+	if cache := s.config.ToolCache; cache != nil && cache.URL != "" {
+		s.toolCache = tool.NewManifestCache(cache.URL, time.Duration(cache.TTLSec)*time.Second)
+	}
 	// Further defaults can be added here later without modifying callers.
 }
 
@@ -65,6 +83,7 @@ func (s *Service) initWorkflowService() {
 	// --------------------------------------------------------------
 	if len(s.config.Builtins) > 0 {
 		for _, svc := range resolveBuiltinServices(s.config.Builtins) {
+			s.annotate(svc, builtinAnnotations[svc.Name()])
 			s.Workflow.Extensions = append(s.Workflow.Extensions, svc)
 		}
 	}
@@ -75,6 +94,9 @@ func (s *Service) initWorkflowService() {
 		s.Workflow.Extensions = append(s.Workflow.Extensions, mcpClientSvc)
 	}
 
+	// Expose asynchronous execution handles (job-start, job-status, ...).
+	s.Workflow.Extensions = append(s.Workflow.Extensions, job.NewService(s, s.jobs))
+
 	if len(s.Workflow.Extensions) > 0 {
 		opts = append(opts, fluxor.WithExtensionServices(s.Workflow.Extensions...))
 	}
@@ -4,7 +4,9 @@ import (
 	"github.com/viant/fluxor/model/types"
 	"github.com/viant/fluxor/service/action/system/patch"
 
+	"github.com/viant/fluxor-mcp/internal/conv"
 	"github.com/viant/fluxor-mcp/mcp/matcher"
+	"github.com/viant/fluxor-mcp/mcp/tool"
 
 	// Built-in action packages – only those with parameter-less New()
 	nop "github.com/viant/fluxor/service/action/nop"
@@ -22,8 +24,19 @@ var builtinFactories = map[string]func() types.Service{
 	"system/patch": func() types.Service { return patch.New() },
 }
 
-// resolveBuiltinServices converts pattern(s) – "*" for all, prefix or exact –
-// into concrete service instances.  Duplicate patterns are ignored.
+// builtinAnnotations holds the behaviour hints of built-in services, keyed
+// like builtinFactories. They are attached to the created instances only, so
+// that an imported or custom service of the same name is not mistaken for one.
+var builtinAnnotations = map[string]*tool.Annotations{
+	"nop":          {ReadOnly: conv.Pointer(true), OpenWorld: conv.Pointer(false)},
+	"printer":      {ReadOnly: conv.Pointer(true), OpenWorld: conv.Pointer(false)},
+	"system/exec":  {ReadOnly: conv.Pointer(false), Destructive: conv.Pointer(true), OpenWorld: conv.Pointer(true)},
+	"system/patch": {ReadOnly: conv.Pointer(false), Destructive: conv.Pointer(true), OpenWorld: conv.Pointer(false)},
+}
+
+// resolveBuiltinServices converts pattern(s) – "*" for all, prefix, exact,
+// glob, "re:" expression or "!" negation evaluated in order – into concrete
+// service instances.  Duplicate patterns are ignored.
 func resolveBuiltinServices(patterns []string) []types.Service {
 	return ResolveServices(patterns, builtinFactories)
 }
@@ -34,11 +47,9 @@ func ResolveServices(patterns []string, factories map[string]func() types.Servic
 
 	add := func(name string) { selected[name] = struct{}{} }
 
-	for _, p := range patterns {
-		for n := range factories {
-			if matcher.Match(p, n) {
-				add(n)
-			}
+	for n := range factories {
+		if matcher.MatchAny(patterns, n) {
+			add(n)
 		}
 	}
 
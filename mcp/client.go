@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"context"
+	"sync"
 
 	"github.com/viant/jsonrpc"
 	protoclient "github.com/viant/mcp-protocol/client"
@@ -60,3 +61,36 @@ func (*defaultClient) Notify(ctx context.Context, notification *jsonrpc.Notifica
 }
 
 func newMcpClient() protoclient.Handler { return &defaultClient{} }
+
+// notificationRouter wraps the client handler of a single upstream
+// connection so that server-initiated notifications reach the components
+// built for that connection (e.g. the tool proxy) in addition to the shared
+// handler.
+type notificationRouter struct {
+	protoclient.Handler
+	mu        sync.RWMutex
+	listeners []func(ctx context.Context, notification *jsonrpc.Notification)
+}
+
+// OnNotification forwards the notification to the wrapped handler and every
+// subscribed listener.
+func (r *notificationRouter) OnNotification(ctx context.Context, notification *jsonrpc.Notification) {
+	r.Handler.OnNotification(ctx, notification)
+	r.mu.RLock()
+	listeners := r.listeners
+	r.mu.RUnlock()
+	for _, listener := range listeners {
+		listener(ctx, notification)
+	}
+}
+
+// Subscribe registers a notification listener.
+func (r *notificationRouter) Subscribe(listener func(ctx context.Context, notification *jsonrpc.Notification)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, listener)
+}
+
+func newNotificationRouter(handler protoclient.Handler) *notificationRouter {
+	return &notificationRouter{Handler: handler}
+}
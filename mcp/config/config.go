@@ -24,6 +24,87 @@ type Config struct {
 	ExtensionTypes []*x.Type
 	Builtins       []string           `yaml:"builtins,omitempty" json:"builtins,omitempty"`
 	MCP            *Group[*MCPClient] `yaml:"mcp,omitempty" json:"mcp,omitempty"`
+	// Workflows lists workflow definitions – YAML files, directories or URLs –
+	// published as MCP tools of the "workflow" service.
+	Workflows []string `yaml:"workflows,omitempty" json:"workflows,omitempty"`
+	// Tools controls how served tools are executed and what they return.
+	Tools *Tools `yaml:"tools,omitempty" json:"tools,omitempty"`
+	// Expose selects the tools offered by the MCP server (serve). Unlike
+	// Builtins it does not affect what workflows can use.
+	Expose *Expose `yaml:"expose,omitempty" json:"expose,omitempty"`
+	// Bootstrap controls how imported MCP servers are connected on startup.
+	Bootstrap *Bootstrap `yaml:"bootstrap,omitempty" json:"bootstrap,omitempty"`
+	// ToolCache persists the tool lists of imported MCP servers.
+	ToolCache *ToolCache `yaml:"toolCache,omitempty" json:"toolCache,omitempty"`
+}
+
+// ToolCache stores the tools/list result of every imported MCP server as
+// <name>.json under URL, a directory or afs URL. A cached list younger than
+// TTLSec (3600 by default) is used on startup without listing tools – the
+// server is connected in the background – and an older one is used when the
+// server is unreachable. Entries are rewritten when the tool set ETag changes.
+type ToolCache struct {
+	URL    string `yaml:"url,omitempty" json:"url,omitempty"`
+	TTLSec int    `yaml:"ttlSec,omitempty" json:"ttlSec,omitempty"`
+}
+
+// Bootstrap configures startup of imported MCP servers. Servers are
+// connected concurrently – at most Concurrency at a time (all when zero) –
+// and servers not registered within TimeoutSec (60 by default) are reported
+// to the MCP error handler.
+type Bootstrap struct {
+	TimeoutSec  int `yaml:"timeoutSec,omitempty" json:"timeoutSec,omitempty"`
+	Concurrency int `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
+}
+
+// Expose lists tool name patterns – "*", a service prefix such as "system/",
+// a glob such as "printer/*", a "re:" expression or a tool name; "!" negates a
+// pattern and patterns are evaluated in order (see matcher.MatchAny). A tool is
+// exposed when Include selects it (all tools when Include is empty) and
+// Exclude does not.
+type Expose struct {
+	Include []string `yaml:"include,omitempty" json:"include,omitempty"`
+	Exclude []string `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+}
+
+// Tools groups tool execution and serving options.
+type Tools struct {
+	// ValidateOutput validates structured output against the advertised
+	// outputSchema; mismatches are returned as tool errors.
+	ValidateOutput bool `yaml:"validateOutput,omitempty" json:"validateOutput,omitempty"`
+	// SkipInputValidation disables checking tool arguments against the
+	// advertised inputSchema before execution.
+	SkipInputValidation bool `yaml:"skipInputValidation,omitempty" json:"skipInputValidation,omitempty"`
+	// TimeoutSec is the default execution timeout; zero means 15 minutes.
+	TimeoutSec int `yaml:"timeoutSec,omitempty" json:"timeoutSec,omitempty"`
+	// MaxConcurrency limits tool executions running at the same time across
+	// all tools; zero means unlimited.
+	MaxConcurrency int `yaml:"maxConcurrency,omitempty" json:"maxConcurrency,omitempty"`
+	// Rules apply per-tool settings to tools whose name matches the pattern.
+	Rules []*ToolRule `yaml:"rules,omitempty" json:"rules,omitempty"`
+	// Naming, when set, sanitizes served tool names for strict MCP hosts.
+	Naming *Naming `yaml:"naming,omitempty" json:"naming,omitempty"`
+}
+
+// Naming sanitizes served tool names to [a-zA-Z0-9_-] of at most MaxLength
+// (64 by default) characters; original names remain accepted.
+type Naming struct {
+	MaxLength int `yaml:"maxLength,omitempty" json:"maxLength,omitempty"`
+}
+
+// ToolRule configures tools whose name matches Pattern (see tool.Match);
+// later matching rules take precedence.
+type ToolRule struct {
+	Pattern string `yaml:"pattern" json:"pattern"`
+	// TimeoutSec overrides the default execution timeout.
+	TimeoutSec int `yaml:"timeoutSec,omitempty" json:"timeoutSec,omitempty"`
+	// MaxConcurrency limits concurrent executions of the matching tools.
+	MaxConcurrency int `yaml:"maxConcurrency,omitempty" json:"maxConcurrency,omitempty"`
+	// Defaults are arguments applied when the caller does not supply them.
+	Defaults map[string]interface{} `yaml:"defaults,omitempty" json:"defaults,omitempty"`
+	// Annotations set behaviour hints (readOnly, destructive, idempotent,
+	// openWorld) advertised with the matching tools.
+	Annotations *Annotations `yaml:"annotations,omitempty" json:"annotations,omitempty"`
 }
 
 func Load(path string) (*Config, error) {
@@ -52,7 +133,9 @@ func (c *Config) Validate() error {
 //   - "prompts/list"
 //   - "prompts/get"
 //
-// When set, these override the default method descriptions.
+// When set, these override the default method descriptions. Imported tools
+// themselves can be renamed, described or hidden through Tools and
+// ToolPrefix.
 type MCPClient struct {
 	*mcp.ClientOptions `yaml:",inline" json:",inline"`
 	Descriptions       map[string]string `yaml:"descriptions,omitempty" json:"descriptions,omitempty"`
@@ -60,4 +143,85 @@ type MCPClient struct {
 	// under meta["metadata"]. This can be used by MCP hosts to receive
 	// any side-channel information.
 	Metadata map[string]interface{} `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	// Tools overrides imported tools keyed by upstream tool name: rename,
+	// rewrite the description or hide a tool.
+	Tools map[string]*Override `yaml:"tools,omitempty" json:"tools,omitempty"`
+	// ToolPrefix is prepended to every imported tool name, e.g. "gh_".
+	ToolPrefix string `yaml:"toolPrefix,omitempty" json:"toolPrefix,omitempty"`
+	// Health configures connection health checks and reconnects.
+	Health *Health `yaml:"health,omitempty" json:"health,omitempty"`
+	// Retry is the retry policy of tool calls; Tools entries can replace it
+	// per tool.
+	Retry *RetryPolicy `yaml:"retry,omitempty" json:"retry,omitempty"`
+	// CircuitBreaker makes tool calls fail fast while the server keeps failing.
+	CircuitBreaker *BreakerPolicy `yaml:"circuitBreaker,omitempty" json:"circuitBreaker,omitempty"`
+	// Lazy registers tools from Manifest, or the tool cache, without
+	// connecting; the server is connected on first use. Without a readable
+	// manifest the server is connected on startup.
+	Lazy bool `yaml:"lazy,omitempty" json:"lazy,omitempty"`
+	// Manifest is a file or URL with the server tools/list result.
+	Manifest string `yaml:"manifest,omitempty" json:"manifest,omitempty"`
+	// ForwardAuth forwards the credentials of callers of served tools.
+	ForwardAuth *ForwardAuth `yaml:"forwardAuth,omitempty" json:"forwardAuth,omitempty"`
+	// Isolation gives callers of served tools their own server sessions.
+	Isolation *Isolation `yaml:"isolation,omitempty" json:"isolation,omitempty"`
+}
+
+// Isolation keys for Isolation.By.
+const (
+	IsolateBySession = "session"
+	IsolateByUser    = "user"
+)
+
+// Isolation keeps a pool of connections to a stateful imported server so that
+// its tools called through the served MCP endpoint do not share server state
+// between callers. By selects the pool key: "session" (default) – one
+// connection per inbound session – or "user" – one per verified caller
+// identity, falling back to the session for callers without one; "user"
+// requires a token verifier (mcp.WithTokenVerifier). Connections unused for
+// IdleSec (300 by default) are dropped; MaxSessions caps the pool size.
+type Isolation struct {
+	By          string `yaml:"by,omitempty" json:"by,omitempty"`
+	IdleSec     int    `yaml:"idleSec,omitempty" json:"idleSec,omitempty"`
+	MaxSessions int    `yaml:"maxSessions,omitempty" json:"maxSessions,omitempty"`
+}
+
+// Credential forwarding modes.
+const (
+	ForwardPassthrough = "passthrough"
+	ForwardExchange    = "exchange"
+)
+
+// ForwardAuth forwards the bearer token of the caller connected to the served
+// MCP endpoint on calls to the imported server. Mode "passthrough" sends the
+// token as is; "exchange" trades it for a scoped token at Exchange.TokenURL
+// (RFC 8693) or with the exchanger set by mcp.WithTokenExchanger. With
+// Required, calls without caller credentials fail instead of using the
+// client's own credentials.
+type ForwardAuth struct {
+	Mode     string         `yaml:"mode,omitempty" json:"mode,omitempty"`
+	Required bool           `yaml:"required,omitempty" json:"required,omitempty"`
+	Exchange *TokenExchange `yaml:"exchange,omitempty" json:"exchange,omitempty"`
+}
+
+// TokenExchange configures an RFC 8693 token exchange endpoint.
+type TokenExchange struct {
+	TokenURL     string `yaml:"tokenURL,omitempty" json:"tokenURL,omitempty"`
+	ClientID     string `yaml:"clientID,omitempty" json:"clientID,omitempty"`
+	ClientSecret string `yaml:"clientSecret,omitempty" json:"clientSecret,omitempty"`
+	Audience     string `yaml:"audience,omitempty" json:"audience,omitempty"`
+	Scope        string `yaml:"scope,omitempty" json:"scope,omitempty"`
+}
+
+// Health configures how an imported MCP server connection is monitored. The
+// server is pinged every PingIntervalSec (30 by default); when a ping fails
+// the client reconnects with exponential backoff from InitialBackoffMs (1000)
+// up to MaxBackoffSec (60), re-initialises the session and re-discovers
+// tools.
+type Health struct {
+	Disabled         bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	PingIntervalSec  int  `yaml:"pingIntervalSec,omitempty" json:"pingIntervalSec,omitempty"`
+	PingTimeoutSec   int  `yaml:"pingTimeoutSec,omitempty" json:"pingTimeoutSec,omitempty"`
+	InitialBackoffMs int  `yaml:"initialBackoffMs,omitempty" json:"initialBackoffMs,omitempty"`
+	MaxBackoffSec    int  `yaml:"maxBackoffSec,omitempty" json:"maxBackoffSec,omitempty"`
 }
@@ -0,0 +1,109 @@
+package config
+
+import (
+	mcpschema "github.com/viant/mcp-protocol/schema"
+)
+
+// Annotations are behaviour hints advertised with a tool; MCP hosts use them
+// to decide, for example, whether to ask the user for confirmation. Unset
+// hints keep the MCP defaults: not read-only, destructive, not idempotent,
+// open world.
+type Annotations struct {
+	Title       string `yaml:"title,omitempty" json:"title,omitempty"`
+	ReadOnly    *bool  `yaml:"readOnly,omitempty" json:"readOnly,omitempty"`
+	Destructive *bool  `yaml:"destructive,omitempty" json:"destructive,omitempty"`
+	Idempotent  *bool  `yaml:"idempotent,omitempty" json:"idempotent,omitempty"`
+	OpenWorld   *bool  `yaml:"openWorld,omitempty" json:"openWorld,omitempty"`
+}
+
+// Merge returns a copy of a with the hints set in other taking precedence.
+func (a *Annotations) Merge(other *Annotations) *Annotations {
+	if a == nil && other == nil {
+		return nil
+	}
+	ret := &Annotations{}
+	if a != nil {
+		*ret = *a
+	}
+	if other == nil {
+		return ret
+	}
+	if other.Title != "" {
+		ret.Title = other.Title
+	}
+	if other.ReadOnly != nil {
+		ret.ReadOnly = other.ReadOnly
+	}
+	if other.Destructive != nil {
+		ret.Destructive = other.Destructive
+	}
+	if other.Idempotent != nil {
+		ret.Idempotent = other.Idempotent
+	}
+	if other.OpenWorld != nil {
+		ret.OpenWorld = other.OpenWorld
+	}
+	return ret
+}
+
+// Schema converts the hints into MCP tool annotations; it returns nil when
+// no hint is set.
+func (a *Annotations) Schema() *mcpschema.ToolAnnotations {
+	if a == nil || (*a == Annotations{}) {
+		return nil
+	}
+	ret := &mcpschema.ToolAnnotations{
+		ReadOnlyHint:    a.ReadOnly,
+		DestructiveHint: a.Destructive,
+		IdempotentHint:  a.Idempotent,
+		OpenWorldHint:   a.OpenWorld,
+	}
+	if a.Title != "" {
+		title := a.Title
+		ret.Title = &title
+	}
+	return ret
+}
+
+// Override customises how an imported tool is exposed.
+type Override struct {
+	// Name replaces the upstream tool name.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// Description replaces the upstream tool description.
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Hidden removes the tool from the proxy.
+	Hidden bool `yaml:"hidden,omitempty" json:"hidden,omitempty"`
+	// Presets are arguments merged into every call, keyed by argument name.
+	Presets map[string]*Preset `yaml:"presets,omitempty" json:"presets,omitempty"`
+	// Retry replaces the client retry policy for this tool.
+	Retry *RetryPolicy `yaml:"retry,omitempty" json:"retry,omitempty"`
+}
+
+// Preset is an argument value applied by the proxy. A locked preset is
+// removed from the input schema and always wins over caller arguments; an
+// unlocked preset stays in the schema as an optional argument whose default is
+// Value and is used only when the caller omits it.
+type Preset struct {
+	Value  interface{} `yaml:"value" json:"value"`
+	Locked bool        `yaml:"locked,omitempty" json:"locked,omitempty"`
+}
+
+// RetryPolicy controls how many times a failed upstream call is attempted.
+// Only errors whose class (transport, internal, timeout or tool) is listed
+// in On – transport and internal when empty – are retried; the backoff
+// doubles after every attempt.
+type RetryPolicy struct {
+	MaxAttempts      int      `yaml:"maxAttempts,omitempty" json:"maxAttempts,omitempty"`
+	InitialBackoffMs int      `yaml:"initialBackoffMs,omitempty" json:"initialBackoffMs,omitempty"`
+	MaxBackoffMs     int      `yaml:"maxBackoffMs,omitempty" json:"maxBackoffMs,omitempty"`
+	On               []string `yaml:"on,omitempty" json:"on,omitempty"`
+}
+
+// BreakerPolicy configures a circuit breaker. After FailureThreshold
+// consecutive upstream failures calls fail fast for OpenSec seconds; the
+// first call after that is let through as a probe which closes the circuit
+// on success or re-opens it on failure.
+type BreakerPolicy struct {
+	FailureThreshold int `yaml:"failureThreshold,omitempty" json:"failureThreshold,omitempty"`
+	OpenSec          int `yaml:"openSec,omitempty" json:"openSec,omitempty"`
+}
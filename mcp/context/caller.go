@@ -0,0 +1,29 @@
+package context
+
+import (
+	"context"
+)
+
+type callerKey string
+
+var CallerKey = callerKey("caller")
+
+// Principal identifies the caller of an inbound MCP request.
+type Principal struct {
+	// Token is the caller bearer token without the "Bearer " prefix.
+	Token string
+	// Subject is the verified caller identity (e.g. the token "sub" claim);
+	// it is empty unless the token was verified.
+	Subject string
+}
+
+// WithCaller attaches the inbound caller.
+func WithCaller(ctx context.Context, caller *Principal) context.Context {
+	return context.WithValue(ctx, CallerKey, caller)
+}
+
+// Caller returns the inbound caller attached to ctx.
+func Caller(ctx context.Context) (*Principal, bool) {
+	caller, ok := ctx.Value(CallerKey).(*Principal)
+	return caller, ok && caller != nil
+}
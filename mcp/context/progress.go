@@ -0,0 +1,33 @@
+package context
+
+import (
+	"context"
+)
+
+type progressKey string
+
+var ProgressKey = progressKey("progress")
+
+// Execution progress stages.
+const (
+	StageScheduled = "scheduled"
+	StageStarted   = "started"
+	StageCompleted = "completed"
+	StageFailed    = "failed"
+)
+
+// Reporter receives execution progress updates, e.g. stage "started" with a
+// human readable message.
+type Reporter func(ctx context.Context, stage, message string)
+
+// WithProgress attaches a progress reporter; a nil reporter disables
+// reporting for downstream calls.
+func WithProgress(ctx context.Context, reporter Reporter) context.Context {
+	return context.WithValue(ctx, ProgressKey, reporter)
+}
+
+// Progress returns the progress reporter attached to ctx.
+func Progress(ctx context.Context) (Reporter, bool) {
+	reporter, ok := ctx.Value(ProgressKey).(Reporter)
+	return reporter, ok && reporter != nil
+}
@@ -0,0 +1,20 @@
+package context
+
+import (
+	"context"
+)
+
+type sessionKey string
+
+var SessionKey = sessionKey("session")
+
+// WithSession attaches the identifier of the inbound MCP session.
+func WithSession(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, SessionKey, id)
+}
+
+// Session returns the inbound MCP session identifier attached to ctx.
+func Session(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(SessionKey).(string)
+	return id, ok && id != ""
+}
@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/viant/fluxor-mcp/mcp/auth"
+	"github.com/viant/fluxor-mcp/mcp/config"
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+	authschema "github.com/viant/mcp-protocol/authorization"
+)
+
+// withCaller attaches the caller of an inbound request, identified by the
+// token the server authorizer put in ctx, so that imported servers configured
+// with forwardAuth receive the caller credentials. The caller identity is set
+// only when the token verifier accepts the token.
+func (s *Service) withCaller(ctx context.Context) context.Context {
+	token, ok := ctx.Value(authschema.TokenKey).(*authschema.Token)
+	if !ok || token == nil {
+		return ctx
+	}
+	if caller := auth.NewPrincipal(token.Token); caller != nil {
+		auth.Verify(ctx, caller, s.tokenVerifier)
+		ctx = mcontext.WithCaller(ctx, caller)
+	}
+	return ctx
+}
+
+// credentials returns the proxy credentials function of an imported server,
+// or nil when caller credentials are not forwarded.
+func (s *Service) credentials(mcpConfig *config.MCPClient) (func(ctx context.Context) (string, error), error) {
+	forward := mcpConfig.ForwardAuth
+	if forward == nil || forward.Mode == "" {
+		return nil, nil
+	}
+	var exchange auth.ExchangeFunc
+	switch forward.Mode {
+	case config.ForwardPassthrough:
+	case config.ForwardExchange:
+		exchange = s.tokenExchanger
+		if options := forward.Exchange; options != nil && options.TokenURL != "" {
+			exchange = auth.NewExchanger(auth.ExchangeOptions{
+				TokenURL:     options.TokenURL,
+				ClientID:     options.ClientID,
+				ClientSecret: options.ClientSecret,
+				Audience:     options.Audience,
+				Scope:        options.Scope,
+			}, nil).Exchange
+		}
+		if exchange == nil {
+			return nil, fmt.Errorf("forwardAuth %q: exchange.tokenURL or a token exchanger is required", mcpConfig.Name)
+		}
+	default:
+		return nil, fmt.Errorf("forwardAuth %q: unsupported mode %q", mcpConfig.Name, forward.Mode)
+	}
+	name := mcpConfig.Name
+	return func(ctx context.Context) (string, error) {
+		caller, ok := mcontext.Caller(ctx)
+		if !ok {
+			if forward.Required {
+				return "", fmt.Errorf("caller credentials required by %q", name)
+			}
+			return "", nil
+		}
+		if exchange == nil {
+			return caller.Token, nil
+		}
+		token, err := exchange(ctx, name, caller.Token)
+		if err != nil {
+			return "", fmt.Errorf("exchange caller token for %q: %w", name, err)
+		}
+		return token, nil
+	}, nil
+}
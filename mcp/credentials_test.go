@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/fluxor-mcp/mcp/config"
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+	"github.com/viant/mcp"
+	authschema "github.com/viant/mcp-protocol/authorization"
+)
+
+func TestServiceCredentials(t *testing.T) {
+	svc := &Service{tokenExchanger: func(ctx context.Context, upstream, token string) (string, error) {
+		return strings.ToUpper(token) + "@" + upstream, nil
+	}}
+	inbound := svc.withCaller(context.WithValue(context.Background(), authschema.TokenKey, &authschema.Token{Token: "Bearer alice"}))
+	anonymous := context.Background()
+
+	testCases := []struct {
+		description string
+		forward     *config.ForwardAuth
+		ctx         context.Context
+		expect      string
+		expectErr   bool
+	}{
+		{description: "passthrough", forward: &config.ForwardAuth{Mode: config.ForwardPassthrough}, ctx: inbound, expect: "alice"},
+		{description: "exchange", forward: &config.ForwardAuth{Mode: config.ForwardExchange}, ctx: inbound, expect: "ALICE@analytics"},
+		{description: "no caller", forward: &config.ForwardAuth{Mode: config.ForwardPassthrough}, ctx: anonymous},
+		{description: "caller required", forward: &config.ForwardAuth{Mode: config.ForwardPassthrough, Required: true}, ctx: anonymous, expectErr: true},
+	}
+	for _, testCase := range testCases {
+		credentials, err := svc.credentials(&config.MCPClient{ClientOptions: &mcp.ClientOptions{Name: "analytics"}, ForwardAuth: testCase.forward})
+		if !assert.NoError(t, err, testCase.description) {
+			continue
+		}
+		token, err := credentials(testCase.ctx)
+		if testCase.expectErr {
+			assert.Error(t, err, testCase.description)
+			continue
+		}
+		assert.NoError(t, err, testCase.description)
+		assert.EqualValues(t, testCase.expect, token, testCase.description)
+	}
+
+	caller, ok := mcontext.Caller(inbound)
+	assert.True(t, ok)
+	assert.EqualValues(t, "alice", caller.Token)
+
+	credentials, err := svc.credentials(&config.MCPClient{ClientOptions: &mcp.ClientOptions{Name: "analytics"}})
+	assert.NoError(t, err)
+	assert.Nil(t, credentials, "not forwarded by default")
+	_, err = (&Service{}).credentials(&config.MCPClient{ClientOptions: &mcp.ClientOptions{Name: "analytics"}, ForwardAuth: &config.ForwardAuth{Mode: config.ForwardExchange}})
+	assert.Error(t, err, "exchange without endpoint or exchanger")
+}
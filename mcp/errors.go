@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/viant/fluxor-mcp/mcp/tool"
+	"github.com/viant/fluxor-mcp/mcp/tool/conversion"
+	"github.com/viant/fluxor/runtime/execution"
+)
+
+// Error codes reported in ExecutionError.Code.
+const (
+	ErrorCodeUnknownTool      = "unknown_tool"
+	ErrorCodeInvalidArguments = "invalid_arguments"
+	ErrorCodeScheduleFailed   = "schedule_failed"
+	ErrorCodeExecutionFailed  = "execution_failed"
+	ErrorCodeTimeout          = "timeout"
+	ErrorCodeCancelled        = "cancelled"
+	ErrorCodeNotCancellable   = "not_cancellable"
+)
+
+// ExecutionError is returned by ExecuteTool when a tool cannot be executed or
+// its execution fails. Served tools report it as an isError result with the
+// error fields as structured content.
+type ExecutionError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Tool      string `json:"tool,omitempty"`
+	Task      string `json:"task,omitempty"`
+	Retryable bool   `json:"retryable"`
+	// Errors lists the offending arguments of an invalid_arguments error.
+	Errors []conversion.FieldError `json:"errors,omitempty"`
+	cause  error
+}
+
+// Error implements error.
+func (e *ExecutionError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Code)
+	if e.Tool != "" {
+		sb.WriteString(": tool " + e.Tool)
+		if e.Task != "" {
+			sb.WriteString(" task " + e.Task)
+		}
+	}
+	sb.WriteString(": " + e.Message)
+	return sb.String()
+}
+
+// Unwrap returns the underlying error, if any.
+func (e *ExecutionError) Unwrap() error { return e.cause }
+
+// newExecutionError classifies an error returned while waiting for an
+// execution; timeouts, open upstream circuits and retryable upstream failures
+// are retryable, cancellations are not.
+func newExecutionError(name string, err error) *ExecutionError {
+	var ret *ExecutionError
+	if errors.As(err, &ret) {
+		return ret
+	}
+	ret = &ExecutionError{Code: ErrorCodeExecutionFailed, Message: err.Error(), Tool: name, cause: err}
+	var callErr *tool.CallError
+	switch {
+	case errors.As(err, &callErr):
+		ret.Retryable = callErr.Retryable
+	case errors.Is(err, context.Canceled):
+		ret.Code = ErrorCodeCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		ret.Code = ErrorCodeTimeout
+		ret.Retryable = true
+	case errors.Is(err, tool.ErrCircuitOpen):
+		ret.Retryable = true
+	}
+	return ret
+}
+
+// failedExecutionError reports the error recorded by a failed execution. The
+// record keeps the error as text, so the upstream error class of a proxied
+// call is recovered from the message.
+func failedExecutionError(name string, anExec *execution.Execution) *ExecutionError {
+	ret := &ExecutionError{Code: ErrorCodeExecutionFailed, Message: anExec.Error, Tool: name, Task: anExec.TaskID}
+	if callErr, ok := tool.ParseCallError(anExec.Error); ok {
+		ret.Message = callErr.Err.Error()
+		ret.Retryable = callErr.Retryable
+		ret.cause = callErr
+	}
+	return ret
+}
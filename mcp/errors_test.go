@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/fluxor-mcp/mcp/tool"
+	"github.com/viant/jsonrpc"
+	mcpschema "github.com/viant/mcp-protocol/schema"
+	mcpclient "github.com/viant/mcp/client"
+)
+
+func TestNewExecutionError(t *testing.T) {
+	testCases := []struct {
+		description     string
+		err             error
+		expectCode      string
+		expectRetryable bool
+	}{
+		{description: "failure", err: errors.New("boom"), expectCode: ErrorCodeExecutionFailed},
+		{description: "deadline", err: fmt.Errorf("wait: %w", context.DeadlineExceeded), expectCode: ErrorCodeTimeout, expectRetryable: true},
+		{description: "timeout in message only", err: errors.New("query timeout exceeded"), expectCode: ErrorCodeExecutionFailed},
+		{description: "circuit in message only", err: errors.New(tool.ErrCircuitOpen.Error()), expectCode: ErrorCodeExecutionFailed},
+		{description: "circuit open", err: fmt.Errorf("call tool %q: %w", "query", tool.ErrCircuitOpen), expectCode: ErrorCodeExecutionFailed, expectRetryable: true},
+		{description: "upstream transport", err: &tool.CallError{Class: tool.ErrorClassTransport, Retryable: true, Err: errors.New("EOF")}, expectCode: ErrorCodeExecutionFailed, expectRetryable: true},
+		{description: "cancelled", err: fmt.Errorf("execution cancelled: %w", context.Canceled), expectCode: ErrorCodeCancelled},
+		{description: "already typed", err: &ExecutionError{Code: ErrorCodeUnknownTool}, expectCode: ErrorCodeUnknownTool},
+	}
+
+	for _, tc := range testCases {
+		actual := newExecutionError("system_exec-execute", tc.err)
+		assert.EqualValues(t, tc.expectCode, actual.Code, tc.description)
+		assert.EqualValues(t, tc.expectRetryable, actual.Retryable, tc.description)
+		if tc.expectCode != ErrorCodeUnknownTool {
+			assert.True(t, errors.Is(actual, tc.err), tc.description)
+		}
+	}
+}
+
+func TestErrorResult(t *testing.T) {
+	err := fmt.Errorf("call: %w", &ExecutionError{Code: ErrorCodeExecutionFailed, Message: "exit code 1", Tool: "system_exec-execute", Task: "run"})
+	res := errorResult(err)
+	assert.True(t, *res.IsError)
+	assert.EqualValues(t, map[string]interface{}{
+		"code":      ErrorCodeExecutionFailed,
+		"message":   "exit code 1",
+		"tool":      "system_exec-execute",
+		"task":      "run",
+		"retryable": false,
+	}, res.StructuredContent)
+	assert.JSONEq(t, `{"code":"execution_failed","message":"exit code 1","tool":"system_exec-execute","task":"run","retryable":false}`, res.Content[0].Text)
+
+	plain := errorResult(errors.New("boom"))
+	assert.Nil(t, plain.StructuredContent)
+	assert.EqualValues(t, "boom", plain.Content[0].Text)
+}
+
+// failingClient is an upstream MCP client listing a single query tool whose
+// calls fail with err.
+type failingClient struct {
+	mcpclient.Interface
+	err error
+}
+
+func (f *failingClient) ListTools(_ context.Context, _ *string, _ ...mcpclient.RequestOption) (*mcpschema.ListToolsResult, error) {
+	return &mcpschema.ListToolsResult{Tools: []mcpschema.Tool{{Name: "query", InputSchema: mcpschema.ToolInputSchema{Type: "object"}}}}, nil
+}
+
+func (f *failingClient) CallTool(_ context.Context, _ *mcpschema.CallToolRequestParams, _ ...mcpclient.RequestOption) (*mcpschema.CallToolResult, error) {
+	return nil, f.err
+}
+
+// TestService_UpstreamFailureRetryable verifies that the class of a failed
+// proxied call survives the execution record: transport failures and open
+// circuits are retryable, rejected requests are not.
+func TestService_UpstreamFailureRetryable(t *testing.T) {
+	ctx := context.Background()
+	testCases := []struct {
+		description     string
+		err             error
+		calls           int
+		expectRetryable bool
+		expectMessage   string
+	}{
+		{description: "transport failure", err: errors.New("connection refused"), calls: 1, expectRetryable: true, expectMessage: `call tool "query" (circuit closed, 1/2 failures): connection refused`},
+		{description: "internal error", err: jsonrpc.NewInternalError("connection reset", nil), calls: 1, expectRetryable: true},
+		{description: "open circuit", err: errors.New("connection refused"), calls: 3, expectRetryable: true},
+		{description: "invalid params", err: jsonrpc.NewError(jsonrpc.InvalidParams, "bad sql", nil), calls: 1},
+	}
+
+	for _, tc := range testCases {
+		svc, err := New(ctx)
+		if !assert.NoError(t, err, tc.description) {
+			continue
+		}
+		proxy, err := tool.NewProxy(ctx, "upstream", &failingClient{err: tc.err},
+			tool.WithCircuitBreaker(&tool.BreakerPolicy{FailureThreshold: 2, OpenSec: 60}))
+		if !assert.NoError(t, err, tc.description) {
+			continue
+		}
+		assert.NoError(t, svc.WorkflowService().Actions().Register(proxy), tc.description)
+		query, err := proxy.Method("query")
+		if !assert.NoError(t, err, tc.description) {
+			continue
+		}
+		svc.runtime = &fakeRuntime{run: func(ctx context.Context) (interface{}, error) {
+			var output interface{}
+			err := query(ctx, map[string]interface{}{}, &output)
+			return output, err
+		}}
+		var execErr *ExecutionError
+		for i := 0; i < tc.calls; i++ {
+			_, err = svc.ExecuteTool(ctx, "upstream-query", map[string]interface{}{}, time.Second)
+		}
+		if assert.ErrorAs(t, err, &execErr, tc.description) {
+			assert.EqualValues(t, ErrorCodeExecutionFailed, execErr.Code, tc.description)
+			assert.EqualValues(t, tc.expectRetryable, execErr.Retryable, tc.description)
+			assert.NotContains(t, execErr.Message, "[upstream", tc.description)
+			if tc.expectMessage != "" {
+				assert.EqualValues(t, tc.expectMessage, execErr.Message, tc.description)
+			}
+		}
+		svc.Shutdown(ctx)
+	}
+}
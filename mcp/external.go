@@ -2,20 +2,31 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/viant/afs"
 	"github.com/viant/fluxor-mcp/mcp/config"
 	"github.com/viant/fluxor-mcp/mcp/discovery"
 	"github.com/viant/fluxor-mcp/mcp/tool"
-	"github.com/viant/mcp"
+	"github.com/viant/fluxor-mcp/mcp/upstream"
+	"github.com/viant/fluxor/model/types"
 	protocolclient "github.com/viant/mcp-protocol/client"
+	mcpclient "github.com/viant/mcp/client"
 	"gopkg.in/yaml.v3"
 )
 
+// DefaultBootstrapTimeout bounds how long startup waits for imported MCP
+// servers to be registered.
+const DefaultBootstrapTimeout = time.Minute
+
 // registerExternalActions loads external Server endpoints specified in the
 // configuration, introspects the available tools and turns each of them into a
-// Fluxor service whose methods proxy the remote calls.
+// Fluxor service whose methods proxy the remote calls. Endpoints are
+// connected concurrently so that a slow or unreachable server delays startup
+// by at most the bootstrap timeout.
 func (s *Service) registerExternalActions(ctx context.Context) error {
 
 	mcpConfigs, err := s.loadMCPClientConfig(ctx)
@@ -25,51 +36,220 @@ func (s *Service) registerExternalActions(ctx context.Context) error {
 	if len(mcpConfigs) == 0 {
 		return nil // nothing to do – no externals configured
 	}
-	for _, mcpConfig := range mcpConfigs {
+	timeout, concurrency := DefaultBootstrapTimeout, len(mcpConfigs)
+	if bootstrap := s.config.Bootstrap; bootstrap != nil {
+		if bootstrap.TimeoutSec > 0 {
+			timeout = time.Duration(bootstrap.TimeoutSec) * time.Second
+		}
+		if bootstrap.Concurrency > 0 {
+			concurrency = bootstrap.Concurrency
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
+	type result struct {
+		index int
+		err   error
+	}
+	results := make(chan result, len(mcpConfigs))
+	limit := make(chan struct{}, concurrency)
+	for i, mcpConfig := range mcpConfigs {
 		// Ensure required defaults are applied so that name/version are never empty.
-		if err = s.RegisterMcpClientTools(ctx, mcpConfig); err != nil {
-			if err = s.mcpErrorHandler(mcpConfig.ClientOptions, err); err != nil {
-				return err
+		mcpConfig.Init()
+		go func(index int, mcpConfig *config.MCPClient) {
+			select {
+			case limit <- struct{}{}:
+				defer func() { <-limit }()
+			case <-ctx.Done():
+				results <- result{index: index, err: ctx.Err()}
+				return
 			}
+			results <- result{index: index, err: s.RegisterMcpClientTools(ctx, mcpConfig)}
+		}(i, mcpConfig)
+	}
+
+	// Every registration step honours ctx, so all results arrive shortly
+	// after the deadline at the latest.
+	errs := make([]error, len(mcpConfigs))
+	for range mcpConfigs {
+		r := <-results
+		errs[r.index] = r.err
+	}
+	for i, mcpConfig := range mcpConfigs {
+		err := errs[i]
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("register mcp client %q: bootstrap timeout %s exceeded", mcpConfig.Name, timeout)
+		}
+		if err == nil {
+			continue
+		}
+		if err = s.mcpErrorHandler(mcpConfig.ClientOptions, err); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-// RegisterMcpClientTools register Server clientHandler
+// RegisterMcpClientTools register Server clientHandler. With mcpConfig.Lazy
+// and a readable manifest the tools are registered without connecting; the
+// server is connected on first use and its tools re-discovered then. With a
+// tool cache, a fresh cached tool list is registered right away while the
+// server is connected in the background, and a stale one is used when the
+// server is unreachable. Nothing is registered once ctx is done, so a server
+// answering after the bootstrap deadline does not show up after being
+// reported as failed.
 func (s *Service) RegisterMcpClientTools(ctx context.Context, mcpConfig *config.MCPClient) error {
-	actions := s.Workflow.Service.Actions()
 	mcpConfig.Init()
 
-	impl := s.ClientHandler()
+	// Every upstream connection gets its own router so that tool list changes
+	// are delivered to the proxy built for that connection only.
+	impl := newNotificationRouter(s.ClientHandler())
 
-	cli, err := mcp.NewClient(impl, mcpConfig.ClientOptions)
+	credentials, err := s.credentials(mcpConfig)
+	if err != nil {
+		return err
+	}
+	if err = s.checkIsolation(mcpConfig); err != nil {
+		return err
+	}
+	manifest, lazy := s.startupManifest(ctx, mcpConfig)
+	dial := func(ctx context.Context) (mcpclient.Interface, error) {
+		conn, err := upstream.Dial(ctx, impl, mcpConfig.ClientOptions)
+		if err != nil {
+			return nil, err
+		}
+		return conn, nil
+	}
+	options := healthOptions(mcpConfig.Health)
+	options.Lazy = manifest != nil
+	cli, err := upstream.New(ctx, mcpConfig.Name, dial, options)
 	if err != nil {
-		return fmt.Errorf("create mcp clientHandler %q: %w", mcpConfig.Name, err)
+		// unreachable – use the cached tool list and connect on first use
+		if manifest, _ = s.cachedManifest(context.WithoutCancel(ctx), mcpConfig); manifest == nil {
+			return fmt.Errorf("create mcp clientHandler %q: %w", mcpConfig.Name, err)
+		}
+		lazy, options.Lazy = true, true
+		if cli, err = upstream.New(ctx, mcpConfig.Name, dial, options); err != nil {
+			return fmt.Errorf("create mcp clientHandler %q: %w", mcpConfig.Name, err)
+		}
 	}
 
-	mcpToolService, err := tool.NewProxy(ctx, mcpConfig.Name, cli)
+	proxyOptions := []tool.ProxyOption{
+		tool.WithOverrides(mcpConfig.Tools), tool.WithPrefix(mcpConfig.ToolPrefix),
+		tool.WithRetry(mcpConfig.Retry), tool.WithCircuitBreaker(mcpConfig.CircuitBreaker),
+		tool.WithCredentials(credentials),
+	}
+	if manifest != nil {
+		proxyOptions = append(proxyOptions, tool.WithManifest(manifest))
+	}
+	mcpToolService, err := tool.NewProxy(ctx, mcpConfig.Name, cli, proxyOptions...)
 	if err != nil {
+		cli.Close()
 		return fmt.Errorf("load tools for %q: %w", mcpConfig.Name, err)
 	}
+	var disc []types.Service
+	if manifest == nil {
+		s.storeManifest(ctx, mcpConfig, mcpToolService)
+		if disc, err = discovery.New(ctx, mcpConfig, cli); err != nil {
+			mcpToolService.Close()
+			cli.Close()
+			return err
+		}
+	}
+	// everything below takes effect – a server that answered after the
+	// bootstrap deadline has already been reported as failed. The deadline is
+	// checked under actionsMu so that it cannot pass between the check and
+	// the registration.
+	s.actionsMu.Lock()
+	if err := s.registerUpstream(ctx, mcpToolService, disc); err != nil {
+		s.actionsMu.Unlock()
+		mcpToolService.Close()
+		cli.Close()
+		return err
+	}
+	impl.Subscribe(mcpToolService.OnNotification)
+	mcpToolService.OnChange(func(ctx context.Context) { s.storeManifest(ctx, mcpConfig, mcpToolService) })
+	mcpToolService.OnChange(s.RefreshTools)
+	// a restarted or lazily connected server may offer a different tool set
+	cli.OnReconnect(func(ctx context.Context) { _ = mcpToolService.Refresh(ctx) })
+	s.addUpstream(cli, mcpToolService)
+	if mcpConfig.Isolation != nil {
+		s.addIsolation(mcpToolService.Name(), mcpConfig.Isolation, mcpConfig.Health, dial)
+	}
+	if manifest != nil {
+		// discovery probes the server capabilities – defer it to first use
+		var once sync.Once
+		cli.OnReconnect(func(ctx context.Context) {
+			once.Do(func() {
+				s.actionsMu.Lock()
+				_ = s.registerDiscovery(ctx, mcpConfig, cli)
+				s.actionsMu.Unlock()
+				s.RefreshTools(ctx)
+			})
+		})
+	}
+	s.actionsMu.Unlock()
+	if health := mcpConfig.Health; health == nil || !health.Disabled {
+		cli.Start()
+	}
+	if manifest != nil && !lazy {
+		// registered from a fresh cache – re-discover tools in the background
+		go func() { _ = cli.Connect(context.Background()) }()
+	}
+	s.RefreshTools(ctx)
+	return nil
+}
 
-	if err := actions.Register(mcpToolService); err != nil {
+// registerUpstream registers the proxy and discovery services of an imported
+// server unless ctx is done. Callers hold actionsMu. Services registered
+// before a failing one stay registered; their calls fail once the caller
+// closes the connection.
+func (s *Service) registerUpstream(ctx context.Context, proxy *tool.Proxy, disc []types.Service) error {
+	if err := ctx.Err(); err != nil {
 		return err
 	}
-	// Register discovery services (resources, prompts) with dynamic prefix.
-	if disc, err := discovery.New(ctx, mcpConfig, cli); err == nil {
-		for _, svc := range disc {
-			if err := actions.Register(svc); err != nil {
-				return err
-			}
+	actions := s.Workflow.Service.Actions()
+	if err := actions.Register(proxy); err != nil {
+		return err
+	}
+	for _, svc := range disc {
+		if err := actions.Register(svc); err != nil {
+			return err
 		}
-	} else {
+	}
+	return nil
+}
+
+// registerDiscovery registers discovery services (resources, prompts) with
+// dynamic prefix.
+func (s *Service) registerDiscovery(ctx context.Context, mcpConfig *config.MCPClient, cli mcpclient.Interface) error {
+	disc, err := discovery.New(ctx, mcpConfig, cli)
+	if err != nil {
 		return err
 	}
+	actions := s.Workflow.Service.Actions()
+	for _, svc := range disc {
+		if err := actions.Register(svc); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// healthOptions converts health configuration into upstream options.
+func healthOptions(health *config.Health) upstream.Options {
+	if health == nil {
+		return upstream.Options{}
+	}
+	return upstream.Options{
+		PingInterval:   time.Duration(health.PingIntervalSec) * time.Second,
+		PingTimeout:    time.Duration(health.PingTimeoutSec) * time.Second,
+		InitialBackoff: time.Duration(health.InitialBackoffMs) * time.Millisecond,
+		MaxBackoff:     time.Duration(health.MaxBackoffSec) * time.Second,
+	}
+}
+
 func (s *Service) ClientHandler() protocolclient.Handler {
 	impl := s.clientHandler
 	if impl == nil {
@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/viant/fluxor-mcp/mcp/config"
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+	"github.com/viant/fluxor-mcp/mcp/tool"
+	"github.com/viant/fluxor-mcp/mcp/upstream"
+)
+
+// isolation routes calls of an imported server to per-caller connections.
+type isolation struct {
+	by   string
+	pool *upstream.Pool
+}
+
+func (s *Service) addIsolation(service string, settings *config.Isolation, health *config.Health, dial upstream.Dialer) {
+	pool := upstream.NewPool(service, dial, upstream.PoolOptions{
+		Idle:           time.Duration(settings.IdleSec) * time.Second,
+		MaxSize:        settings.MaxSessions,
+		Health:         healthOptions(health),
+		HealthDisabled: health != nil && health.Disabled,
+	})
+	s.mu.Lock()
+	if s.isolated == nil {
+		s.isolated = make(map[string]*isolation)
+	}
+	s.isolated[service] = &isolation{by: settings.By, pool: pool}
+	s.mu.Unlock()
+	pool.Start()
+}
+
+// isolate places the caller's pooled connection in ctx (mcontext.WithClient)
+// when the named tool is imported from a server configured with isolation.
+// The returned release function hands the connection back to the pool.
+func (s *Service) isolate(ctx context.Context, name, session string) (context.Context, func(), error) {
+	service := tool.Name(s.canonicalToolName(name)).Service()
+	s.mu.RLock()
+	isolated := s.isolated[service]
+	s.mu.RUnlock()
+	if isolated == nil {
+		return ctx, func() {}, nil
+	}
+	client, release, err := isolated.pool.Acquire(ctx, isolationKey(ctx, isolated.by, session))
+	if err != nil {
+		return ctx, nil, fmt.Errorf("connect %v session: %w", service, err)
+	}
+	return mcontext.WithClient(ctx, client), release, nil
+}
+
+// checkIsolation refuses isolation by user without a token verifier.
+func (s *Service) checkIsolation(mcpConfig *config.MCPClient) error {
+	if settings := mcpConfig.Isolation; settings != nil && settings.By == config.IsolateByUser && s.tokenVerifier == nil {
+		return fmt.Errorf("isolation %q: by %q requires a token verifier (mcp.WithTokenVerifier)", mcpConfig.Name, config.IsolateByUser)
+	}
+	return nil
+}
+
+// isolationKey returns the pool key of the caller: the verified caller
+// subject when isolating by user, the session otherwise.
+func isolationKey(ctx context.Context, by, session string) string {
+	if by == config.IsolateByUser {
+		if caller, ok := mcontext.Caller(ctx); ok && caller.Subject != "" {
+			return "user:" + caller.Subject
+		}
+	}
+	return "session:" + session
+}
+
+func (s *Service) closeIsolation() {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, isolated := range s.isolated {
+		isolated.pool.Close()
+	}
+}
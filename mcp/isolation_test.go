@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/fluxor-mcp/mcp/config"
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+	"github.com/viant/mcp"
+)
+
+func TestIsolationKey(t *testing.T) {
+	testCases := []struct {
+		description string
+		by          string
+		caller      *mcontext.Principal
+		expect      string
+	}{
+		{description: "session by default", caller: &mcontext.Principal{Subject: "alice"}, expect: "session:7"},
+		{description: "user subject", by: config.IsolateByUser, caller: &mcontext.Principal{Token: "t", Subject: "alice"}, expect: "user:alice"},
+		{description: "unverified user", by: config.IsolateByUser, caller: &mcontext.Principal{Token: "opaque"}, expect: "session:7"},
+		{description: "anonymous user", by: config.IsolateByUser, expect: "session:7"},
+	}
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		if testCase.caller != nil {
+			ctx = mcontext.WithCaller(ctx, testCase.caller)
+		}
+		assert.Equal(t, testCase.expect, isolationKey(ctx, testCase.by, "7"), testCase.description)
+	}
+}
+
+func TestServiceCheckIsolation(t *testing.T) {
+	verifier := func(ctx context.Context, token string) (string, error) { return "alice", nil }
+	testCases := []struct {
+		description string
+		isolation   *config.Isolation
+		service     *Service
+		expectErr   bool
+	}{
+		{description: "no isolation", service: &Service{}},
+		{description: "by session", isolation: &config.Isolation{}, service: &Service{}},
+		{description: "by user unverified", isolation: &config.Isolation{By: config.IsolateByUser}, service: &Service{}, expectErr: true},
+		{description: "by user verified", isolation: &config.Isolation{By: config.IsolateByUser}, service: &Service{tokenVerifier: verifier}},
+	}
+	for _, testCase := range testCases {
+		err := testCase.service.checkIsolation(&config.MCPClient{ClientOptions: &mcp.ClientOptions{Name: "analytics"}, Isolation: testCase.isolation})
+		assert.EqualValues(t, testCase.expectErr, err != nil, testCase.description)
+	}
+}
@@ -0,0 +1,11 @@
+// Package job tracks tools executed asynchronously. A job handle is returned
+// as soon as the underlying Fluxor execution is scheduled; companion actions
+// (start, status, result, cancel) let agents poll long running work instead of
+// holding an MCP request open.
+//
+// The job ID is the Fluxor execution ID. A job belongs to the caller that
+// started it (see Owner) and is reported as unknown to anyone else. Jobs are
+// kept in a Store: ExecutionStore persists them in the Fluxor execution store
+// so that they survive a restart, MemoryStore holds them in the serving
+// process only.
+package job
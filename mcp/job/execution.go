@@ -0,0 +1,96 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/viant/fluxor-mcp/internal/conv"
+	"github.com/viant/fluxor/runtime/execution"
+)
+
+// ExecutionDAO loads and saves Fluxor execution records by ID, e.g. the
+// execution DAO of the Fluxor service.
+type ExecutionDAO interface {
+	Save(ctx context.Context, anExec *execution.Execution) error
+	Load(ctx context.Context, id string) (*execution.Execution, error)
+}
+
+// ExecutionStore keeps jobs in the Fluxor execution store. Every job is saved
+// as an execution of the job start action, with the job snapshot and its
+// owner as output, when it starts and again when it finishes. The record ID is
+// the job ID prefixed with "job:" so that it never replaces the record of the
+// tool execution itself. Jobs of this process are served from memory, other
+// jobs are restored from their record, so they survive a restart.
+type ExecutionStore struct {
+	dao  ExecutionDAO
+	live *MemoryStore
+}
+
+// NewExecutionStore creates a job store over dao; finished jobs are served
+// from memory for the retention (zero uses DefaultRetention) and loaded from
+// dao afterwards.
+func NewExecutionStore(dao ExecutionDAO, retention time.Duration) *ExecutionStore {
+	return &ExecutionStore{dao: dao, live: NewMemoryStore(retention)}
+}
+
+// record is the persisted form of a job.
+type record struct {
+	State
+	Owner string `json:"owner,omitempty"`
+}
+
+// Add saves a job and saves it again once it finishes.
+func (s *ExecutionStore) Add(ctx context.Context, job *Job) error {
+	if err := s.save(ctx, job); err != nil {
+		return err
+	}
+	_ = s.live.Add(ctx, job)
+	go func() {
+		<-job.Done()
+		_ = s.save(context.WithoutCancel(ctx), job) // best effort – on failure the record stays running
+	}()
+	return nil
+}
+
+// Get returns a job by ID or nil.
+func (s *ExecutionStore) Get(ctx context.Context, id string) (*Job, error) {
+	if job, _ := s.live.Get(ctx, id); job != nil {
+		return job, nil
+	}
+	anExec, err := s.dao.Load(ctx, recordID(id))
+	if err != nil {
+		return nil, fmt.Errorf("load job %v: %w", id, err)
+	}
+	if anExec == nil {
+		return nil, nil
+	}
+	rec := &record{}
+	if err := conv.Convert(anExec.Output, rec); err != nil {
+		return nil, fmt.Errorf("load job %v: %w", id, err)
+	}
+	if rec.ID != id {
+		return nil, nil
+	}
+	return Restore(rec.State, rec.Owner), nil
+}
+
+func (s *ExecutionStore) save(ctx context.Context, job *Job) error {
+	state := job.State()
+	anExec, err := execution.NewAtHocExecution(ServiceName, "start", &StartInput{Name: state.Tool})
+	if err != nil {
+		return fmt.Errorf("save job %v: %w", state.ID, err)
+	}
+	anExec.ID = recordID(state.ID)
+	anExec.Output = &record{State: state, Owner: job.Owner()}
+	anExec.Error = state.Error
+	if err := s.dao.Save(ctx, anExec); err != nil {
+		return fmt.Errorf("save job %v: %w", state.ID, err)
+	}
+	return nil
+}
+
+// recordID returns the execution record ID of a job.
+func recordID(id string) string {
+	return ServiceName + ":" + id
+}
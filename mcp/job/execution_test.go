@@ -0,0 +1,99 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+	"github.com/viant/fluxor/runtime/execution"
+)
+
+// memoryDAO keeps execution records as JSON, as a persistent store would.
+type memoryDAO struct {
+	mu      sync.Mutex
+	records map[string][]byte
+}
+
+func (d *memoryDAO) Save(_ context.Context, anExec *execution.Execution) error {
+	data, err := json.Marshal(anExec.Output)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.records[anExec.ID] = data
+	return nil
+}
+
+func (d *memoryDAO) Load(_ context.Context, id string) (*execution.Execution, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	data, ok := d.records[id]
+	if !ok {
+		return nil, fmt.Errorf("execution %v not found", id)
+	}
+	var output map[string]interface{}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, err
+	}
+	return &execution.Execution{ID: id, Output: output}, nil
+}
+
+func TestExecutionStore_Restart(t *testing.T) {
+	dao := &memoryDAO{records: map[string][]byte{}}
+	alice := mcontext.WithCaller(context.Background(), &mcontext.Principal{Subject: "alice"})
+	bob := mcontext.WithCaller(context.Background(), &mcontext.Principal{Subject: "bob"})
+
+	store := NewExecutionStore(dao, 0)
+	aJob := New("1", "nop-nop", Owner(alice), nil)
+	if !assert.NoError(t, store.Add(alice, aJob)) {
+		return
+	}
+	aJob.Complete("ok", nil)
+	assert.Eventually(t, func() bool {
+		restored, err := NewExecutionStore(dao, 0).Get(alice, "1")
+		return err == nil && restored != nil && restored.State().Status == StatusCompleted
+	}, time.Second, 10*time.Millisecond)
+
+	restarted := NewExecutionStore(dao, 0)
+	restored, err := Lookup(alice, restarted, "1")
+	if assert.NoError(t, err) {
+		state := restored.Wait(alice, time.Second)
+		assert.Equal(t, "nop-nop", state.Tool)
+		assert.Equal(t, "ok", state.Output)
+		assert.ErrorIs(t, restored.Cancel(), ErrNotCancellable)
+	}
+	_, err = Lookup(bob, restarted, "1")
+	assert.EqualError(t, err, "unknown job: 1")
+	_, err = Lookup(alice, restarted, "2")
+	assert.EqualError(t, err, "load job 2: execution job:2 not found")
+}
+
+func TestOwner(t *testing.T) {
+	testCases := []struct {
+		description string
+		ctx         context.Context
+		expect      string
+	}{
+		{description: "no caller", ctx: context.Background()},
+		{description: "session", ctx: mcontext.WithSession(context.Background(), "7"), expect: "session:7"},
+		{
+			description: "verified subject wins over the session",
+			ctx:         mcontext.WithCaller(mcontext.WithSession(context.Background(), "7"), &mcontext.Principal{Subject: "alice"}),
+			expect:      "user:alice",
+		},
+		{
+			description: "unverified token uses the session",
+			ctx:         mcontext.WithCaller(mcontext.WithSession(context.Background(), "7"), &mcontext.Principal{Token: "t"}),
+			expect:      "session:7",
+		},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expect, Owner(tc.ctx), tc.description)
+	}
+}
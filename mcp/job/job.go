@@ -0,0 +1,160 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+)
+
+// ErrNotCancellable is returned when cancelling a job whose execution cannot
+// be aborted; the Fluxor runtime offers no API to stop a scheduled execution.
+var ErrNotCancellable = errors.New("execution cannot be cancelled")
+
+// Status represents the lifecycle stage of a job.
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// State is a point-in-time snapshot of a job.
+type State struct {
+	ID          string      `json:"id" description:"job identifier (Fluxor execution ID)"`
+	Tool        string      `json:"tool" description:"executed tool name"`
+	Status      Status      `json:"status" description:"running, completed, failed or cancelled"`
+	Output      interface{} `json:"output,omitempty" description:"tool output once completed"`
+	Error       string      `json:"error,omitempty" description:"failure reason"`
+	CreatedAt   time.Time   `json:"createdAt"`
+	CompletedAt *time.Time  `json:"completedAt,omitempty"`
+	Cancellable bool        `json:"cancellable" description:"whether job-cancel can abort the execution"`
+}
+
+// Done reports whether the job reached a terminal status.
+func (s *State) Done() bool { return s.Status != StatusRunning }
+
+// Job tracks one asynchronous tool execution.
+type Job struct {
+	mu     sync.RWMutex
+	state  State
+	owner  string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New creates a running job belonging to owner (see Owner); cancel aborts the
+// underlying execution, a nil cancel makes the job not cancellable.
+func New(id, tool, owner string, cancel context.CancelFunc) *Job {
+	return &Job{
+		state:  State{ID: id, Tool: tool, Status: StatusRunning, CreatedAt: time.Now(), Cancellable: cancel != nil},
+		owner:  owner,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+}
+
+// Restore rebuilds a job from a persisted snapshot. The restored job is not
+// tied to an execution of this process: it is not cancellable and only
+// reports the persisted state.
+func Restore(state State, owner string) *Job {
+	state.Cancellable = false
+	ret := &Job{state: state, owner: owner, done: make(chan struct{})}
+	if state.Done() {
+		close(ret.done)
+	}
+	return ret
+}
+
+// ID returns the job identifier.
+func (j *Job) ID() string { return j.state.ID }
+
+// Owner returns the identity of the caller that started the job.
+func (j *Job) Owner() string { return j.owner }
+
+// State returns a snapshot of the job.
+func (j *Job) State() State {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.state
+}
+
+// Done returns a channel closed once the job reaches a terminal status.
+func (j *Job) Done() <-chan struct{} { return j.done }
+
+// Wait blocks until the job is done, ctx is cancelled or timeout elapses
+// (zero timeout returns immediately) and returns the job snapshot.
+func (j *Job) Wait(ctx context.Context, timeout time.Duration) State {
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-j.done:
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+	}
+	return j.State()
+}
+
+// Complete records the outcome of the execution. It has no effect once the
+// job is done, so a late result cannot override a cancellation.
+func (j *Job) Complete(output interface{}, err error) {
+	if err != nil {
+		j.finish(StatusFailed, nil, err.Error())
+		return
+	}
+	j.finish(StatusCompleted, output, "")
+}
+
+// Cancel marks the job cancelled and aborts the execution. A job that is not
+// cancellable is left untouched and ErrNotCancellable is returned.
+func (j *Job) Cancel() error {
+	if j.cancel == nil {
+		return ErrNotCancellable
+	}
+	if j.finish(StatusCancelled, nil, "cancelled") {
+		j.cancel()
+	}
+	return nil
+}
+
+func (j *Job) finish(status Status, output interface{}, errorMessage string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.state.Done() {
+		return false
+	}
+	now := time.Now()
+	j.state.Status = status
+	j.state.Output = output
+	j.state.Error = errorMessage
+	j.state.CompletedAt = &now
+	close(j.done)
+	return true
+}
+
+// Store holds jobs by ID.
+type Store interface {
+	// Add registers a job.
+	Add(ctx context.Context, job *Job) error
+	// Get returns a job by ID or nil.
+	Get(ctx context.Context, id string) (*Job, error)
+}
+
+// Owner returns the identity jobs started with ctx belong to: the verified
+// caller subject, the inbound MCP session otherwise. It is empty for callers
+// outside an MCP request, e.g. Go code calling Service.StartTool directly.
+func Owner(ctx context.Context) string {
+	if caller, ok := mcontext.Caller(ctx); ok && caller.Subject != "" {
+		return "user:" + caller.Subject
+	}
+	if session, ok := mcontext.Session(ctx); ok {
+		return "session:" + session
+	}
+	return ""
+}
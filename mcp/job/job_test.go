@@ -0,0 +1,71 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJob_Lifecycle(t *testing.T) {
+	testCases := []struct {
+		description  string
+		run          func(j *Job)
+		expectStatus Status
+		expectOutput interface{}
+		expectError  string
+		expectCancel bool
+	}{
+		{
+			description:  "completed",
+			run:          func(j *Job) { j.Complete("ok", nil) },
+			expectStatus: StatusCompleted,
+			expectOutput: "ok",
+		},
+		{
+			description:  "failed",
+			run:          func(j *Job) { j.Complete(nil, errors.New("boom")) },
+			expectStatus: StatusFailed,
+			expectError:  "boom",
+		},
+		{
+			description:  "cancelled ignores late result",
+			run:          func(j *Job) { assert.NoError(t, j.Cancel()); j.Complete("late", nil) },
+			expectStatus: StatusCancelled,
+			expectError:  "cancelled",
+			expectCancel: true,
+		},
+		{
+			description:  "cancel after completion is a no-op",
+			run:          func(j *Job) { j.Complete("ok", nil); assert.NoError(t, j.Cancel()) },
+			expectStatus: StatusCompleted,
+			expectOutput: "ok",
+		},
+	}
+
+	for _, tc := range testCases {
+		cancelled := false
+		aJob := New("1", "nop-nop", "", func() { cancelled = true })
+		assert.Equal(t, StatusRunning, aJob.State().Status, tc.description)
+		tc.run(aJob)
+		state := aJob.Wait(context.Background(), time.Second)
+		assert.Equal(t, tc.expectStatus, state.Status, tc.description)
+		assert.Equal(t, tc.expectOutput, state.Output, tc.description)
+		assert.Equal(t, tc.expectError, state.Error, tc.description)
+		assert.NotNil(t, state.CompletedAt, tc.description)
+		assert.Equal(t, tc.expectCancel, cancelled, tc.description)
+	}
+}
+
+func TestJob_NotCancellable(t *testing.T) {
+	aJob := New("1", "nop-nop", "", nil)
+	assert.False(t, aJob.State().Cancellable)
+	assert.ErrorIs(t, aJob.Cancel(), ErrNotCancellable)
+	assert.Equal(t, StatusRunning, aJob.State().Status)
+	aJob.Complete("ok", nil)
+	state := aJob.Wait(context.Background(), time.Second)
+	assert.Equal(t, StatusCompleted, state.Status)
+	assert.Equal(t, "ok", state.Output)
+}
@@ -0,0 +1,49 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/viant/fluxor-mcp/internal/syncmap"
+)
+
+// DefaultRetention is how long finished jobs are kept for result retrieval.
+const DefaultRetention = time.Hour
+
+// MemoryStore keeps jobs in the memory of the serving process; finished jobs
+// are dropped after the retention.
+type MemoryStore struct {
+	jobs      *syncmap.Map[*Job]
+	retention time.Duration
+}
+
+// NewMemoryStore creates a memory job store; zero retention uses
+// DefaultRetention.
+func NewMemoryStore(retention time.Duration) *MemoryStore {
+	if retention == 0 {
+		retention = DefaultRetention
+	}
+	return &MemoryStore{jobs: syncmap.NewRegistry[*Job](), retention: retention}
+}
+
+// Add registers a job and evicts expired ones.
+func (s *MemoryStore) Add(_ context.Context, job *Job) error {
+	s.evict()
+	s.jobs.Set(job.ID(), job)
+	return nil
+}
+
+// Get returns a job by ID or nil.
+func (s *MemoryStore) Get(_ context.Context, id string) (*Job, error) {
+	return s.jobs.Get(id), nil
+}
+
+func (s *MemoryStore) evict() {
+	threshold := time.Now().Add(-s.retention)
+	for _, job := range s.jobs.List() {
+		state := job.State()
+		if state.CompletedAt != nil && state.CompletedAt.Before(threshold) {
+			s.jobs.Delete(state.ID)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_EvictsExpired(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(time.Millisecond)
+	done := New("done", "nop-nop", "", nil)
+	done.Complete("ok", nil)
+	running := New("running", "nop-nop", "", nil)
+	store.Add(ctx, done)
+	store.Add(ctx, running)
+	time.Sleep(5 * time.Millisecond)
+	store.Add(ctx, New("next", "nop-nop", "", nil))
+
+	for id, expected := range map[string]bool{"done": false, "running": true, "next": true} {
+		aJob, err := store.Get(ctx, id)
+		assert.NoError(t, err, id)
+		assert.Equal(t, expected, aJob != nil, id)
+	}
+}
@@ -0,0 +1,149 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/viant/fluxor-mcp/internal/conv"
+	"github.com/viant/fluxor/model/types"
+)
+
+// ServiceName is the Fluxor service name of the job companion actions, which
+// are exposed as job-start, job-status, job-result and job-cancel tools.
+const ServiceName = "job"
+
+// Starter starts tools asynchronously.
+type Starter interface {
+	StartTool(ctx context.Context, name string, args map[string]interface{}, timeout time.Duration) (*Job, error)
+}
+
+// StartInput defines job-start arguments.
+type StartInput struct {
+	Name       string                 `json:"name" description:"tool to run, e.g. system_exec-execute"`
+	Arguments  map[string]interface{} `json:"arguments,omitempty" description:"tool arguments"`
+	TimeoutSec int                    `json:"timeoutSec,omitempty" description:"execution timeout in seconds"`
+}
+
+// LookupInput identifies a job.
+type LookupInput struct {
+	ID string `json:"id" description:"job identifier returned by job-start"`
+}
+
+// ResultInput defines job-result arguments.
+type ResultInput struct {
+	ID      string `json:"id" description:"job identifier returned by job-start"`
+	WaitSec int    `json:"waitSec,omitempty" description:"seconds to wait for completion before returning"`
+}
+
+// Service exposes job handling as Fluxor actions.
+type Service struct {
+	starter   Starter
+	store     Store
+	sigs      types.Signatures
+	executors map[string]types.Executable
+}
+
+func (s *Service) Name() string              { return ServiceName }
+func (s *Service) Methods() types.Signatures { return s.sigs }
+func (s *Service) Method(name string) (types.Executable, error) {
+	if e, ok := s.executors[name]; ok {
+		return e, nil
+	}
+	return nil, types.NewMethodNotFoundError(name)
+}
+
+// NewService builds the job companion service.
+func NewService(starter Starter, store Store) *Service {
+	s := &Service{starter: starter, store: store, executors: map[string]types.Executable{}}
+	outT := reflect.TypeOf(&State{})
+
+	s.register("start", "Start a tool asynchronously and return its job handle; only the caller that started a job can read or cancel it", reflect.TypeOf(&StartInput{}), outT,
+		func(ctx context.Context, input interface{}) (*State, error) {
+			in := &StartInput{}
+			if err := conv.Convert(input, in); err != nil {
+				return nil, err
+			}
+			job, err := s.starter.StartTool(ctx, in.Name, in.Arguments, time.Duration(in.TimeoutSec)*time.Second)
+			if err != nil {
+				return nil, err
+			}
+			state := job.State()
+			return &state, nil
+		})
+
+	s.register("status", "Report the status of a job without its output", reflect.TypeOf(&LookupInput{}), outT,
+		func(ctx context.Context, input interface{}) (*State, error) {
+			in := &LookupInput{}
+			if err := conv.Convert(input, in); err != nil {
+				return nil, err
+			}
+			job, err := Lookup(ctx, s.store, in.ID)
+			if err != nil {
+				return nil, err
+			}
+			state := job.State()
+			state.Output = nil
+			return &state, nil
+		})
+
+	s.register("result", "Fetch the result of a job, optionally waiting for completion", reflect.TypeOf(&ResultInput{}), outT,
+		func(ctx context.Context, input interface{}) (*State, error) {
+			in := &ResultInput{}
+			if err := conv.Convert(input, in); err != nil {
+				return nil, err
+			}
+			job, err := Lookup(ctx, s.store, in.ID)
+			if err != nil {
+				return nil, err
+			}
+			state := job.Wait(ctx, time.Duration(in.WaitSec)*time.Second)
+			return &state, nil
+		})
+
+	s.register("cancel", "Cancel a running job and abort its execution; fails for jobs that are not cancellable", reflect.TypeOf(&LookupInput{}), outT,
+		func(ctx context.Context, input interface{}) (*State, error) {
+			in := &LookupInput{}
+			if err := conv.Convert(input, in); err != nil {
+				return nil, err
+			}
+			job, err := Lookup(ctx, s.store, in.ID)
+			if err != nil {
+				return nil, err
+			}
+			if err := job.Cancel(); err != nil {
+				return nil, fmt.Errorf("cancel job %v: %w", in.ID, err)
+			}
+			state := job.State()
+			return &state, nil
+		})
+	return s
+}
+
+// Lookup returns the job with the supplied ID from store. A job started by
+// another caller (see Owner) is reported as unknown, like a missing one.
+func Lookup(ctx context.Context, store Store, id string) (*Job, error) {
+	job, err := store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil || job.Owner() != Owner(ctx) {
+		return nil, fmt.Errorf("unknown job: %v", id)
+	}
+	return job, nil
+}
+
+func (s *Service) register(name, description string, in, out reflect.Type, call func(ctx context.Context, input interface{}) (*State, error)) {
+	s.sigs = append(s.sigs, types.Signature{Name: name, Description: description, Input: in, Output: out})
+	s.executors[name] = func(ctx context.Context, input, output interface{}) error {
+		state, err := call(ctx, input)
+		if err != nil {
+			return err
+		}
+		if output != nil {
+			return conv.Convert(state, output)
+		}
+		return nil
+	}
+}
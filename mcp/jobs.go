@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/viant/fluxor-mcp/internal/conv"
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+	"github.com/viant/fluxor-mcp/mcp/job"
+)
+
+// StartTool schedules a tool execution and returns immediately with a job
+// handle. The job ID is the underlying Fluxor execution ID; the job belongs to
+// the caller of ctx (see job.Owner) and its progress can be inspected with
+// Job. The execution is detached from ctx cancellation so that it outlives the
+// request that started it; the Fluxor runtime cannot abort it, so the job is
+// not cancellable.
+// Timeout and concurrency settings of the tools configuration apply as in
+// ExecuteTool; StartTool blocks while the tool's concurrency limit is reached.
+// Exposure is not checked: the MCP server refuses job-start calls of tools it
+// does not expose before they are scheduled.
+func (s *Service) StartTool(ctx context.Context, name string, args map[string]interface{}, timeout time.Duration) (*job.Job, error) {
+	name = s.canonicalToolName(name)
+	policy := s.toolPolicy(name)
+	if timeout == 0 {
+		timeout = policy.timeout
+	}
+	release, err := policy.acquire(ctx)
+	if err != nil {
+		return nil, newExecutionError(name, err)
+	}
+	// the starting request completes right away – never report on its token.
+	runCtx := mcontext.WithProgress(context.WithoutCancel(ctx), nil)
+	anExec, waitFn, err := s.scheduleTool(runCtx, name, policy.arguments(args))
+	if err != nil {
+		release()
+		return nil, err
+	}
+	aJob := job.New(anExec.ID, name, job.Owner(ctx), nil)
+	go func() {
+		defer release()
+		aJob.Complete(s.awaitTool(runCtx, name, anExec, waitFn, timeout))
+	}()
+	if err := s.jobs.Add(ctx, aJob); err != nil {
+		return nil, newExecutionError(name, fmt.Errorf("execution %s is running untracked: %w", anExec.ID, err))
+	}
+	return aJob, nil
+}
+
+// Job returns the job with the supplied ID if it was started by the caller
+// of ctx (see job.Owner).
+func (s *Service) Job(ctx context.Context, id string) (*job.Job, error) {
+	return job.Lookup(ctx, s.jobs, id)
+}
+
+// CancelJob marks a running job cancelled and aborts its execution; finished
+// jobs are left untouched. Jobs started by StartTool are not cancellable as
+// the Fluxor runtime cannot abort an execution: a not_cancellable
+// ExecutionError is returned and the job keeps running.
+func (s *Service) CancelJob(ctx context.Context, id string) (*job.State, error) {
+	aJob, err := s.Job(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := aJob.Cancel(); err != nil {
+		return nil, &ExecutionError{Code: ErrorCodeNotCancellable, Tool: aJob.State().Tool, Message: err.Error(), cause: err}
+	}
+	state := aJob.State()
+	return &state, nil
+}
+
+// checkJobCall refuses a job tool call received by the MCP server before it
+// is scheduled: job-start of a tool that is not exposed, and job-status,
+// job-result or job-cancel of a job the caller did not start. name is the
+// job tool name and method the job action.
+func (s *Service) checkJobCall(ctx context.Context, name, method string, args map[string]interface{}) error {
+	if method == "start" {
+		in := &job.StartInput{}
+		if err := conv.Convert(args, in); err != nil {
+			return &ExecutionError{Code: ErrorCodeInvalidArguments, Tool: name, Message: err.Error(), cause: err}
+		}
+		if started := s.canonicalToolName(in.Name); !s.IsExposed(started) {
+			return &ExecutionError{Code: ErrorCodeUnknownTool, Tool: started, Message: fmt.Sprintf("unknown tool: %s", started)}
+		}
+		return nil
+	}
+	in := &job.LookupInput{}
+	if err := conv.Convert(args, in); err != nil {
+		return &ExecutionError{Code: ErrorCodeInvalidArguments, Tool: name, Message: err.Error(), cause: err}
+	}
+	if _, err := s.Job(ctx, in.ID); err != nil {
+		return &ExecutionError{Code: ErrorCodeExecutionFailed, Tool: name, Message: err.Error(), cause: err}
+	}
+	return nil
+}
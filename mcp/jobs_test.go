@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/fluxor-mcp/mcp/config"
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+	"github.com/viant/fluxor-mcp/mcp/job"
+	mcpschema "github.com/viant/mcp-protocol/schema"
+)
+
+// TestService_StartToolExposure verifies that job-start called through the
+// MCP server is refused before scheduling when it starts a tool excluded from
+// exposure, while local callers still can start it.
+func TestService_StartToolExposure(t *testing.T) {
+	ctx := context.Background()
+	svc, err := New(ctx, WithConfig(&config.Config{Expose: &config.Expose{Exclude: []string{"printer/*"}}}))
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	runtime := &fakeRuntime{}
+	svc.runtime = runtime
+
+	runtime.run = func(ctx context.Context) (interface{}, error) { return "printed", nil }
+	entry, err := svc.LookupTool("job-start")
+	if !assert.NoError(t, err) {
+		return
+	}
+	result, rpcErr := entry.Handler(ctx, &mcpschema.CallToolRequest{Params: mcpschema.CallToolRequestParams{Name: "job-start", Arguments: map[string]interface{}{"name": "printer-print"}}})
+	assert.Nil(t, rpcErr)
+	if assert.NotNil(t, result.IsError) {
+		assert.True(t, *result.IsError)
+		assert.Contains(t, result.Content[0].Text, "unknown tool: printer-print")
+	}
+	assert.Empty(t, runtime.state("exec-1"), "job-start not scheduled")
+
+	runtime.run = func(ctx context.Context) (interface{}, error) { return "printed", nil }
+	aJob, err := svc.StartTool(ctx, "printer-print", map[string]interface{}{"message": "hi"}, 0)
+	if assert.NoError(t, err) {
+		<-aJob.Done()
+		assert.EqualValues(t, job.StatusCompleted, aJob.State().Status)
+	}
+}
+
+// TestService_StartToolLimit verifies that job-start called as a tool does
+// not hold the slot of a concurrency rule matching the started tool as well.
+func TestService_StartToolLimit(t *testing.T) {
+	ctx := context.Background()
+	svc, err := New(ctx, WithConfig(&config.Config{Tools: &config.Tools{
+		Rules: []*config.ToolRule{{Pattern: "*", MaxConcurrency: 1}},
+	}}))
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	start, err := svc.WorkflowService().Actions().Lookup(job.ServiceName).Method("start")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var runs atomic.Int32
+	svc.runtime = &fakeRuntime{run: func(ctx context.Context) (interface{}, error) {
+		if runs.Add(1) > 1 {
+			return "printed", nil
+		}
+		state := &job.State{}
+		err := start(ctx, &job.StartInput{Name: "printer-print", Arguments: map[string]interface{}{"message": "hi"}}, state)
+		return state, err
+	}}
+
+	output, err := svc.ExecuteTool(ctx, "job-start", map[string]interface{}{"name": "printer-print"}, time.Second)
+	if !assert.NoError(t, err) {
+		return
+	}
+	aJob, err := svc.Job(ctx, output.(*job.State).ID)
+	if assert.NoError(t, err) {
+		<-aJob.Done()
+		assert.EqualValues(t, job.StatusCompleted, aJob.State().Status)
+	}
+}
+
+// TestService_JobOwner verifies that a job is only visible to the caller that
+// started it and that job tools of other callers are refused before
+// scheduling.
+func TestService_JobOwner(t *testing.T) {
+	ctx := context.Background()
+	svc, err := New(ctx)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	runtime := &fakeRuntime{run: func(ctx context.Context) (interface{}, error) { return "printed", nil }}
+	svc.runtime = runtime
+
+	owner, other := mcontext.WithSession(ctx, "1"), mcontext.WithSession(ctx, "2")
+	aJob, err := svc.StartTool(owner, "printer-print", map[string]interface{}{"message": "hi"}, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = svc.Job(owner, aJob.ID())
+	assert.NoError(t, err)
+	_, err = svc.Job(other, aJob.ID())
+	assert.EqualError(t, err, "unknown job: "+aJob.ID())
+
+	entry, err := svc.LookupTool("job-status")
+	if !assert.NoError(t, err) {
+		return
+	}
+	result, rpcErr := entry.Handler(other, &mcpschema.CallToolRequest{Params: mcpschema.CallToolRequestParams{Name: "job-status", Arguments: map[string]interface{}{"id": aJob.ID()}}})
+	assert.Nil(t, rpcErr)
+	if assert.NotNil(t, result.IsError) {
+		assert.True(t, *result.IsError)
+		assert.Contains(t, result.Content[0].Text, "unknown job: "+aJob.ID())
+	}
+	assert.Empty(t, runtime.state("exec-2"), "job-status not scheduled")
+}
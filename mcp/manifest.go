@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/viant/fluxor-mcp/mcp/config"
+	"github.com/viant/fluxor-mcp/mcp/tool"
+)
+
+// startupManifest returns the manifest an imported server proxy is built from
+// without listing tools, and whether the server is connected lazily (on first
+// use) rather than in the background. A nil manifest means the server is
+// connected and listed on startup.
+func (s *Service) startupManifest(ctx context.Context, mcpConfig *config.MCPClient) (*tool.Manifest, bool) {
+	if mcpConfig.Lazy && mcpConfig.Manifest != "" {
+		if manifest, err := tool.LoadManifest(ctx, mcpConfig.Manifest); err == nil {
+			return manifest, true
+		}
+	}
+	manifest, fresh := s.cachedManifest(ctx, mcpConfig)
+	switch {
+	case manifest == nil:
+		return nil, false
+	case mcpConfig.Lazy:
+		return manifest, true
+	case fresh:
+		return manifest, false
+	}
+	return nil, false
+}
+
+// cachedManifest returns the cached manifest of an imported server, if any,
+// and whether it is fresh.
+func (s *Service) cachedManifest(ctx context.Context, mcpConfig *config.MCPClient) (*tool.Manifest, bool) {
+	if s.toolCache == nil {
+		return nil, false
+	}
+	manifest, fresh, err := s.toolCache.Load(ctx, mcpConfig.Name)
+	if err != nil || manifest == nil {
+		return nil, false
+	}
+	return manifest, fresh
+}
+
+// storeManifest caches the tools currently offered by an imported server.
+func (s *Service) storeManifest(ctx context.Context, mcpConfig *config.MCPClient, proxy *tool.Proxy) {
+	if s.toolCache == nil {
+		return
+	}
+	_ = s.toolCache.Store(ctx, mcpConfig.Name, proxy.Manifest())
+}
@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/fluxor-mcp/mcp/config"
+	"github.com/viant/fluxor-mcp/mcp/upstream"
+	"github.com/viant/mcp"
+)
+
+// TestService_ToolCacheOffline verifies that an unreachable imported server
+// is registered from its cached tool list.
+func TestService_ToolCacheOffline(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	manifest := `{"tools":[{"name":"query","inputSchema":{"type":"object","properties":{"sql":{"type":"string"}}}}],"createdAt":"2020-01-01T00:00:00Z"}`
+	if !assert.NoError(t, os.WriteFile(filepath.Join(cacheDir, "offline.json"), []byte(manifest), 0644)) {
+		return
+	}
+	options := &mcp.ClientOptions{Name: "offline", Version: "v1"}
+	options.Transport.Type = "stdio"
+	options.Transport.Command = filepath.Join(cacheDir, "missing-server")
+
+	svc, err := New(ctx, WithConfig(&config.Config{
+		Builtins:  []string{"printer"},
+		MCP:       &config.Group[*config.MCPClient]{Items: []*config.MCPClient{{ClientOptions: options}}},
+		ToolCache: &config.ToolCache{URL: cacheDir},
+		Bootstrap: &config.Bootstrap{TimeoutSec: 1},
+	}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = svc.LookupTool("offline-query")
+	assert.NoError(t, err, "tool registered from cache")
+	status := svc.UpstreamStatus()
+	if assert.Len(t, status, 1) {
+		assert.EqualValues(t, upstream.StateIdle, status[0].State, "connected on first use")
+	}
+
+	// the service was never started with Start; Shutdown still closes it
+	assert.NoError(t, svc.Shutdown(ctx))
+	status = svc.UpstreamStatus()
+	if assert.Len(t, status, 1) {
+		assert.EqualValues(t, upstream.StateClosed, status[0].State, "closed on shutdown")
+	}
+}
+
+// TestService_RegisterAfterDeadline verifies that a registration whose
+// deadline has passed – and which is therefore reported as failed – leaves no
+// tools behind.
+func TestService_RegisterAfterDeadline(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	manifest := `{"tools":[{"name":"query","inputSchema":{"type":"object","properties":{"sql":{"type":"string"}}}}],"createdAt":"2020-01-01T00:00:00Z"}`
+	if !assert.NoError(t, os.WriteFile(filepath.Join(cacheDir, "offline.json"), []byte(manifest), 0644)) {
+		return
+	}
+	options := &mcp.ClientOptions{Name: "offline", Version: "v1"}
+	options.Transport.Type = "stdio"
+	options.Transport.Command = filepath.Join(cacheDir, "missing-server")
+
+	svc, err := New(ctx, WithConfig(&config.Config{
+		Builtins:  []string{"printer"},
+		ToolCache: &config.ToolCache{URL: cacheDir},
+	}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer svc.Shutdown(ctx)
+
+	expired, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+	defer cancel()
+	err = svc.RegisterMcpClientTools(expired, &config.MCPClient{ClientOptions: options})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, svc.Workflow.Service.Actions().Lookup("offline"), "no tool registered after the deadline")
+	assert.Empty(t, svc.UpstreamStatus())
+}
@@ -1,15 +1,126 @@
 package matcher
 
-import "strings"
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
 
 // Match reports whether name satisfies pattern using common CLI semantics
-// adopted across the project.
+// adopted across the project:
+//   - "*"           – matches everything.
+//   - "re:<expr>"   – regular expression, e.g. "re:^system/(exec|patch)$".
+//   - glob          – patterns containing *, ? or [...] match the whole name;
+//     "*" and "?" stay within a "/" separated segment while "**" spans
+//     segments, e.g. "system/*/run", "system/**".
+//   - "!<pattern>"  – negation of pattern.
+//   - anything else – prefix match, e.g. "system/" or "system/exec".
 func Match(pattern, name string) bool {
-	if pattern == "*" {
-		return true
+	if strings.HasPrefix(pattern, "!") {
+		return !Match(pattern[1:], name)
 	}
-	if pattern == "" {
+	switch {
+	case pattern == "*":
+		return true
+	case pattern == "":
 		return false
+	case strings.HasPrefix(pattern, "re:"):
+		expr := compile(pattern, func() string { return pattern[3:] })
+		return expr != nil && expr.MatchString(name)
+	case IsGlob(pattern):
+		expr := compile(pattern, func() string { return globExpr(pattern) })
+		return expr != nil && expr.MatchString(name)
 	}
 	return strings.HasPrefix(name, pattern)
 }
+
+// MatchAny evaluates patterns in order, the last matching pattern decides:
+// a plain pattern selects the name, a "!" pattern deselects it. When the first
+// pattern is a negation evaluation starts with every name selected, so
+// ["system/", "!system/exec"] means all of system except system/exec and
+// ["!system/exec"] means everything except system/exec.
+func MatchAny(patterns []string, name string) bool {
+	return MatchAnyFunc(patterns, func(pattern string) bool {
+		return Match(pattern, name)
+	})
+}
+
+// MatchAnyFunc evaluates patterns in order like MatchAny, using match to test
+// a single pattern without its "!" prefix.
+func MatchAnyFunc(patterns []string, match func(pattern string) bool) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	matched := strings.HasPrefix(patterns[0], "!")
+	for _, pattern := range patterns {
+		if negated := strings.HasPrefix(pattern, "!"); negated {
+			if match(pattern[1:]) {
+				matched = false
+			}
+			continue
+		}
+		if match(pattern) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// IsGlob reports whether pattern uses glob syntax.
+func IsGlob(pattern string) bool {
+	return pattern != "*" && strings.ContainsAny(pattern, "*?[")
+}
+
+var expressions sync.Map // pattern → *regexp.Regexp (nil when invalid)
+
+func compile(pattern string, expr func() string) *regexp.Regexp {
+	if cached, ok := expressions.Load(pattern); ok {
+		return cached.(*regexp.Regexp)
+	}
+	compiled, err := regexp.Compile(expr())
+	if err != nil {
+		compiled = nil // invalid patterns never match
+	}
+	expressions.Store(pattern, compiled)
+	return compiled
+}
+
+// globExpr translates a glob pattern into an anchored regular expression.
+func globExpr(pattern string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' { // "**/" also matches no segment
+					i++
+					sb.WriteString("(?:.*/)?")
+					continue
+				}
+				sb.WriteString(".*")
+				continue
+			}
+			sb.WriteString("[^/]*")
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end == -1 {
+				sb.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
@@ -23,6 +23,27 @@ func TestMatch(t *testing.T) {
 		// Prefix matches with "_"
 		{"system_", "system_exec", true},
 		{"sys_", "system_exec", false},
+
+		// Globs
+		{"system/*", "system/exec", true},
+		{"system/*", "system/exec/run", false},
+		{"system/*/run", "system/exec/run", true},
+		{"system/*/run", "system/exec/stop", false},
+		{"system/**", "system/exec/run", true},
+		{"**/run", "system/exec/run", true},
+		{"**/run", "run", true},
+		{"system/exe?", "system/exec", true},
+		{"system/[ep]*", "system/patch", true},
+		{"system/[!ep]*", "system/patch", false},
+
+		// Regular expressions
+		{"re:^system/(exec|patch)$", "system/exec", true},
+		{"re:^system/(exec|patch)$", "system/storage", false},
+		{"re:[", "anything", false},
+
+		// Negation
+		{"!system/exec", "system/exec", false},
+		{"!system/exec", "printer", true},
 	}
 
 	for i, tc := range testCases {
@@ -31,3 +52,27 @@ func TestMatch(t *testing.T) {
 		}
 	}
 }
+
+func TestMatchAny(t *testing.T) {
+	var testCases = []struct {
+		patterns  []string
+		candidate string
+		matched   bool
+	}{
+		{nil, "system/exec", false},
+		{[]string{"printer", "nop"}, "nop", true},
+		{[]string{"system/", "!system/exec"}, "system/patch", true},
+		{[]string{"system/", "!system/exec"}, "system/exec", false},
+		{[]string{"system/", "!system/exec"}, "printer", false},
+		{[]string{"!system/exec"}, "printer", true},
+		{[]string{"!system/exec"}, "system/exec", false},
+		{[]string{"*", "!system/**", "system/patch"}, "system/patch", true},
+		{[]string{"*", "!system/**", "system/patch"}, "system/exec", false},
+	}
+
+	for i, tc := range testCases {
+		if got := MatchAny(tc.patterns, tc.candidate); got != tc.matched {
+			t.Fatalf("[%d] MatchAny(%q, %q) = %v; expected %v", i, tc.patterns, tc.candidate, got, tc.matched)
+		}
+	}
+}
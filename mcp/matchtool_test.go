@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/viant/fluxor-mcp/mcp/config"
 )
 
 // TestServiceMatchTools verifies that the MatchTools helper applies the same
@@ -59,3 +60,26 @@ func TestServiceMatchTools(t *testing.T) {
 	assert.EqualValues(t, 1, len(exact))
 	assert.EqualValues(t, any, exact[0].Metadata.Name)
 }
+
+// TestServiceIsExposed verifies include/exclude filtering of served tools.
+func TestServiceIsExposed(t *testing.T) {
+	testCases := []struct {
+		description string
+		expose      *config.Expose
+		name        string
+		expected    bool
+	}{
+		{description: "no expose section", name: "system_exec-execute", expected: true},
+		{description: "included service", expose: &config.Expose{Include: []string{"printer/*", "analytics/"}}, name: "printer-print", expected: true},
+		{description: "included client", expose: &config.Expose{Include: []string{"printer/*", "analytics/"}}, name: "analytics-query", expected: true},
+		{description: "not included", expose: &config.Expose{Include: []string{"printer/*"}}, name: "system_exec-execute", expected: false},
+		{description: "excluded", expose: &config.Expose{Exclude: []string{"system/exec"}}, name: "system_exec-execute", expected: false},
+		{description: "exclude wins", expose: &config.Expose{Include: []string{"*"}, Exclude: []string{"system_patch-apply"}}, name: "system_patch-apply", expected: false},
+		{description: "other tools stay", expose: &config.Expose{Exclude: []string{"system/exec"}}, name: "system_patch-apply", expected: true},
+	}
+
+	for _, tc := range testCases {
+		svc := &Service{config: &config.Config{Expose: tc.expose}}
+		assert.EqualValues(t, tc.expected, svc.IsExposed(tc.name), tc.description)
+	}
+}
@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/viant/fluxor-mcp/mcp/config"
+	"github.com/viant/fluxor-mcp/mcp/job"
+	"github.com/viant/fluxor-mcp/mcp/tool"
+)
+
+// DefaultToolTimeout applies when neither the caller nor the tools
+// configuration sets an execution timeout.
+const DefaultToolTimeout = 15 * time.Minute
+
+// toolPolicy is the effective execution policy of a tool resolved from the
+// tools configuration section.
+type toolPolicy struct {
+	timeout  time.Duration
+	defaults map[string]interface{}
+	limits   []chan struct{}
+}
+
+// toolPolicy resolves the timeout, default arguments and concurrency limits
+// of the named tool. Job companion tools are not limited: job-start takes a
+// slot of the tool it starts, and the others only read job state.
+func (s *Service) toolPolicy(name string) *toolPolicy {
+	policy := &toolPolicy{timeout: DefaultToolTimeout}
+	tools := s.config.Tools
+	if tools == nil {
+		return policy
+	}
+	limited := tool.Name(name).Service() != job.ServiceName
+	if tools.TimeoutSec > 0 {
+		policy.timeout = time.Duration(tools.TimeoutSec) * time.Second
+	}
+	if limited && tools.MaxConcurrency > 0 {
+		policy.limits = append(policy.limits, s.concurrencyLimit(nil, tools.MaxConcurrency))
+	}
+	for _, rule := range toolRules(tools, name) {
+		if rule.TimeoutSec > 0 {
+			policy.timeout = time.Duration(rule.TimeoutSec) * time.Second
+		}
+		if limited && rule.MaxConcurrency > 0 {
+			policy.limits = append(policy.limits, s.concurrencyLimit(rule, rule.MaxConcurrency))
+		}
+		for k, v := range rule.Defaults {
+			if policy.defaults == nil {
+				policy.defaults = make(map[string]interface{})
+			}
+			policy.defaults[k] = v
+		}
+	}
+	return policy
+}
+
+// toolRules returns the rules of tools applicable to the tool name, in
+// declaration order.
+func toolRules(tools *config.Tools, name string) []*config.ToolRule {
+	if tools == nil {
+		return nil
+	}
+	var result []*config.ToolRule
+	for _, rule := range tools.Rules {
+		if rule != nil && tool.Match(rule.Pattern, name) {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+// concurrencyLimit returns the semaphore of a rule; a nil rule denotes the
+// global limit.
+func (s *Service) concurrencyLimit(rule *config.ToolRule, size int) chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.limits == nil {
+		s.limits = make(map[*config.ToolRule]chan struct{})
+	}
+	limit, ok := s.limits[rule]
+	if !ok {
+		limit = make(chan struct{}, size)
+		s.limits[rule] = limit
+	}
+	return limit
+}
+
+// arguments merges configured default arguments with the supplied ones;
+// supplied arguments take precedence.
+func (p *toolPolicy) arguments(args map[string]interface{}) map[string]interface{} {
+	if len(p.defaults) == 0 {
+		return args
+	}
+	merged := make(map[string]interface{}, len(p.defaults)+len(args))
+	for k, v := range p.defaults {
+		merged[k] = v
+	}
+	for k, v := range args {
+		merged[k] = v
+	}
+	return merged
+}
+
+// acquire blocks until every applicable concurrency slot is available or ctx
+// is done. The returned function releases the slots.
+func (p *toolPolicy) acquire(ctx context.Context) (func(), error) {
+	acquired := 0
+	release := func() {
+		for i := acquired - 1; i >= 0; i-- {
+			<-p.limits[i]
+		}
+	}
+	for _, limit := range p.limits {
+		select {
+		case limit <- struct{}{}:
+			acquired++
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
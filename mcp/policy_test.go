@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/fluxor-mcp/mcp/config"
+)
+
+func TestServiceToolPolicy(t *testing.T) {
+	svc := &Service{config: &config.Config{Tools: &config.Tools{
+		TimeoutSec:     60,
+		MaxConcurrency: 4,
+		Rules: []*config.ToolRule{
+			{Pattern: "system/", TimeoutSec: 30, Defaults: map[string]interface{}{"abortOnError": true, "env": "dev"}},
+			{Pattern: "system_exec-execute", TimeoutSec: 120, MaxConcurrency: 1, Defaults: map[string]interface{}{"env": "prod"}},
+		},
+	}}}
+
+	testCases := []struct {
+		description    string
+		name           string
+		args           map[string]interface{}
+		expectTimeout  time.Duration
+		expectLimits   int
+		expectArgument map[string]interface{}
+	}{
+		{
+			description:    "global defaults",
+			name:           "printer-print",
+			args:           map[string]interface{}{"message": "hi"},
+			expectTimeout:  time.Minute,
+			expectLimits:   1,
+			expectArgument: map[string]interface{}{"message": "hi"},
+		},
+		{
+			description:    "prefix rule",
+			name:           "system_patch-apply",
+			expectTimeout:  30 * time.Second,
+			expectLimits:   1,
+			expectArgument: map[string]interface{}{"abortOnError": true, "env": "dev"},
+		},
+		{
+			description:    "later rule wins, caller arguments win",
+			name:           "system_exec-execute",
+			args:           map[string]interface{}{"abortOnError": false},
+			expectTimeout:  2 * time.Minute,
+			expectLimits:   2,
+			expectArgument: map[string]interface{}{"abortOnError": false, "env": "prod"},
+		},
+	}
+
+	for _, tc := range testCases {
+		policy := svc.toolPolicy(tc.name)
+		assert.EqualValues(t, tc.expectTimeout, policy.timeout, tc.description)
+		assert.Len(t, policy.limits, tc.expectLimits, tc.description)
+		assert.EqualValues(t, tc.expectArgument, policy.arguments(tc.args), tc.description)
+	}
+}
+
+func TestToolPolicy_Acquire(t *testing.T) {
+	svc := &Service{config: &config.Config{Tools: &config.Tools{
+		Rules: []*config.ToolRule{{Pattern: "system_exec", MaxConcurrency: 1}},
+	}}}
+	policy := svc.toolPolicy("system_exec-execute")
+	release, err := policy.acquire(context.Background())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = svc.toolPolicy("system_exec-execute").acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "limit shared across calls")
+
+	release()
+	release, err = policy.acquire(context.Background())
+	assert.NoError(t, err)
+	release()
+}
@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+	"github.com/viant/jsonrpc"
+	"github.com/viant/jsonrpc/transport"
+	mcpschema "github.com/viant/mcp-protocol/schema"
+)
+
+// Execution progress stages reported on the caller's progressToken.
+const (
+	ProgressScheduled = mcontext.StageScheduled
+	ProgressStarted   = mcontext.StageStarted
+	ProgressCompleted = mcontext.StageCompleted
+	ProgressFailed    = mcontext.StageFailed
+)
+
+// newProgressReporter returns a reporter that sends notifications/progress
+// for the supplied token. Progress increases with every reported stage.
+func newProgressReporter(notifier transport.Notifier, token mcpschema.ProgressToken) mcontext.Reporter {
+	var mu sync.Mutex
+	var progress float64
+	return func(ctx context.Context, stage, message string) {
+		mu.Lock()
+		progress++
+		params := &mcpschema.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      progress,
+			Message:       &message,
+		}
+		mu.Unlock()
+		data, err := json.Marshal(params)
+		if err != nil {
+			return
+		}
+		notification := &jsonrpc.Notification{Jsonrpc: jsonrpc.Version, Method: mcpschema.MethodNotificationProgress, Params: data}
+		_ = notifier.Notify(ctx, notification) // best effort – progress must never fail the call
+	}
+}
+
+// reportProgress forwards an execution stage to the reporter attached to ctx.
+func reportProgress(ctx context.Context, stage, tool, detail string) {
+	reporter, ok := mcontext.Progress(ctx)
+	if !ok {
+		return
+	}
+	message := fmt.Sprintf("%s %s", tool, stage)
+	if detail != "" {
+		message += ": " + detail
+	}
+	reporter(ctx, stage, message)
+}
@@ -0,0 +1,24 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/viant/fluxor"
+	"github.com/viant/fluxor/runtime/execution"
+)
+
+var _ executionRuntime = (*fluxor.Runtime)(nil)
+
+// executionRuntime is the part of the Fluxor runtime tools are executed on.
+type executionRuntime interface {
+	ScheduleExecution(ctx context.Context, exec *execution.Execution) (func(time.Duration) (*execution.Execution, error), error)
+}
+
+// executionRuntime returns the runtime tool executions are scheduled on.
+func (s *Service) executionRuntime() executionRuntime {
+	if s.runtime != nil {
+		return s.runtime
+	}
+	return s.Workflow.Runtime
+}
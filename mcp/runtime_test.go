@@ -0,0 +1,166 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+	"github.com/viant/fluxor-mcp/mcp/job"
+	"github.com/viant/fluxor/runtime/execution"
+)
+
+// fakeRuntime runs scheduled executions in process so that tool execution can
+// be tested without the Fluxor runtime's processors.
+type fakeRuntime struct {
+	// run executes the scheduled action.
+	run func(ctx context.Context) (interface{}, error)
+	mu  sync.Mutex
+	seq int
+	// states holds the state of every execution by ID.
+	states map[string]string
+}
+
+func (r *fakeRuntime) ScheduleExecution(ctx context.Context, exec *execution.Execution) (func(time.Duration) (*execution.Execution, error), error) {
+	// executions outlive the scheduling request like in the Fluxor runtime.
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r.mu.Lock()
+	r.seq++
+	exec.ID = fmt.Sprintf("exec-%d", r.seq)
+	if r.states == nil {
+		r.states = map[string]string{}
+	}
+	r.states[exec.ID] = "running"
+	r.mu.Unlock()
+	done := make(chan *execution.Execution, 1)
+	go func() {
+		defer cancel()
+		completed := *exec
+		output, err := r.run(runCtx)
+		completed.Output = output
+		state := "completed"
+		if err != nil {
+			completed.Error = err.Error()
+			state = "failed"
+		}
+		r.setState(exec.ID, state)
+		done <- &completed
+	}()
+	return func(timeout time.Duration) (*execution.Execution, error) {
+		select {
+		case completed := <-done:
+			return completed, nil
+		case <-time.After(timeout):
+			return nil, fmt.Errorf("execution %s timed out", exec.ID)
+		}
+	}, nil
+}
+
+func (r *fakeRuntime) setState(id, state string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[id] = state
+}
+
+func (r *fakeRuntime) state(id string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.states[id]
+}
+
+// TestService_ExecutionProgress verifies that the scheduled, started and
+// completed stages of a tool are reported.
+func TestService_ExecutionProgress(t *testing.T) {
+	ctx := context.Background()
+	svc, err := New(ctx)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	svc.runtime = &fakeRuntime{
+		run: func(ctx context.Context) (interface{}, error) { return "printed", nil },
+	}
+	var mu sync.Mutex
+	var messages []string
+	ctx = mcontext.WithProgress(ctx, func(ctx context.Context, stage, message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		messages = append(messages, message)
+	})
+
+	output, err := svc.ExecuteTool(ctx, "printer-print", map[string]interface{}{"message": "hi"}, time.Second)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "printed", output)
+	assert.EqualValues(t, []string{
+		"printer-print scheduled: exec-1",
+		"printer-print started",
+		"printer-print completed",
+	}, messages)
+}
+
+// TestService_CancelledExecution verifies that cancelling the request stops
+// waiting and reports that the execution, which the runtime cannot abort,
+// keeps running.
+func TestService_CancelledExecution(t *testing.T) {
+	ctx := context.Background()
+	svc, err := New(ctx)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	started, release := make(chan struct{}), make(chan struct{})
+	runtime := &fakeRuntime{run: func(ctx context.Context) (interface{}, error) {
+		close(started)
+		<-release
+		return "printed", nil
+	}}
+	svc.runtime = runtime
+
+	callCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		<-started
+		cancel()
+	}()
+	_, err = svc.ExecuteTool(callCtx, "printer-print", map[string]interface{}{"message": "hi"}, time.Minute)
+	var execErr *ExecutionError
+	if assert.ErrorAs(t, err, &execErr) {
+		assert.EqualValues(t, ErrorCodeCancelled, execErr.Code)
+	}
+	assert.ErrorIs(t, err, job.ErrNotCancellable)
+	assert.EqualValues(t, "running", runtime.state("exec-1"))
+	close(release)
+	assert.Eventually(t, func() bool { return runtime.state("exec-1") == "completed" }, time.Second, 10*time.Millisecond)
+}
+
+// TestService_CancelJob verifies that cancelling a job is refused instead of
+// reporting a cancellation the runtime cannot carry out.
+func TestService_CancelJob(t *testing.T) {
+	ctx := context.Background()
+	svc, err := New(ctx)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	release := make(chan struct{})
+	svc.runtime = &fakeRuntime{run: func(ctx context.Context) (interface{}, error) {
+		<-release
+		return "printed", nil
+	}}
+
+	aJob, err := svc.StartTool(ctx, "printer-print", map[string]interface{}{"message": "hi"}, time.Minute)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, aJob.State().Cancellable)
+	_, err = svc.CancelJob(ctx, aJob.ID())
+	var execErr *ExecutionError
+	if assert.ErrorAs(t, err, &execErr) {
+		assert.EqualValues(t, ErrorCodeNotCancellable, execErr.Code)
+	}
+	assert.ErrorIs(t, err, job.ErrNotCancellable)
+	assert.EqualValues(t, job.StatusRunning, aJob.State().Status)
+	close(release)
+	state := aJob.Wait(ctx, time.Second)
+	assert.EqualValues(t, job.StatusCompleted, state.Status)
+	assert.EqualValues(t, "printed", state.Output)
+}
@@ -2,21 +2,162 @@ package mcp
 
 import (
 	"context"
+	"strconv"
+	"sync/atomic"
 
+	"github.com/viant/fluxor-mcp/internal/conv"
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+	"github.com/viant/fluxor-mcp/mcp/tool"
+	"github.com/viant/jsonrpc"
 	"github.com/viant/jsonrpc/transport"
 	protocolclient "github.com/viant/mcp-protocol/client"
 	"github.com/viant/mcp-protocol/logger"
+	mcpschema "github.com/viant/mcp-protocol/schema"
 	serverproto "github.com/viant/mcp-protocol/server"
 )
 
-// NewHandler returns an Server implementer that exposes the already-built
-// shared tool registry. Every incoming connection therefore reuses the same
-// Registry instance – tools are registered once during Service bootstrap
-// rather than on each connection.
+// NewHandler returns an Server implementer backed by the shared live tool
+// registry; sessions are notified of tool changes until ctx is done.
 func (s *Service) NewHandler(ctx context.Context, notifier transport.Notifier, l logger.Logger, cli protocolclient.Operations) (serverproto.Handler, error) {
 	impl := serverproto.NewDefaultHandler(notifier, l, cli)
-	for _, tool := range s.Tools() {
-		impl.Registry.ToolRegistry.Put(tool.Metadata.Name, tool)
+	impl.Registry = s.toolRegistry()
+	if notifier != nil {
+		s.addSession(notifier)
+		if done := ctx.Done(); done != nil {
+			go func() {
+				<-done
+				s.removeSession(notifier)
+			}()
+		}
 	}
-	return impl, nil
+	session := strconv.FormatUint(atomic.AddUint64(&s.sessionSeq, 1), 10)
+	return &serverHandler{DefaultHandler: impl, service: s, session: session}, nil
+}
+
+// serverHandler decorates the default handler with service specific
+// behaviour shared by all sessions.
+type serverHandler struct {
+	*serverproto.DefaultHandler
+	service *Service
+	// session identifies the inbound session for upstream isolation and job
+	// ownership.
+	session string
+}
+
+// Initialize advertises tool list change notifications on top of the
+// capabilities computed by the default handler.
+func (h *serverHandler) Initialize(ctx context.Context, init *mcpschema.InitializeRequestParams, result *mcpschema.InitializeResult) {
+	h.DefaultHandler.Initialize(ctx, init, result)
+	result.Capabilities.Tools = &mcpschema.ServerCapabilitiesTools{ListChanged: conv.Pointer(true)}
+}
+
+// CallTool attaches a progress reporter when the caller supplied a
+// progressToken, so that execution stages are sent back as
+// notifications/progress, the caller identity and session for credential
+// forwarding and job ownership and, for isolated imported servers, the
+// caller's own upstream connection.
+func (h *serverHandler) CallTool(ctx context.Context, jRequest *jsonrpc.TypedRequest[*mcpschema.CallToolRequest]) (*mcpschema.CallToolResult, *jsonrpc.Error) {
+	if h.service != nil {
+		ctx = mcontext.WithSession(h.service.withCaller(ctx), h.session)
+	}
+	if h.service != nil && jRequest.Request != nil {
+		var release func()
+		var err error
+		if ctx, release, err = h.service.isolate(ctx, jRequest.Request.Params.Name, h.session); err != nil {
+			return nil, jsonrpc.NewInternalError(err.Error(), nil)
+		}
+		defer release()
+	}
+	if token, ok := ctx.Value(mcpschema.TokenProgressContextKey).(mcpschema.ProgressToken); ok && h.Notifier != nil {
+		ctx = mcontext.WithProgress(ctx, newProgressReporter(h.Notifier, token))
+	}
+	return h.DefaultHandler.CallTool(ctx, jRequest)
+}
+
+// RefreshTools re-synchronises the served tool registry with the Fluxor
+// action registry and notifies connected sessions. Call it after registering
+// actions directly on WorkflowService().Actions().
+func (s *Service) RefreshTools(ctx context.Context) {
+	s.indexToolNames()
+	s.mu.RLock()
+	registry := s.registry
+	s.mu.RUnlock()
+	if registry == nil {
+		return // nothing is served yet – the registry is built on first use
+	}
+	s.registryMu.Lock()
+	s.syncTools(registry)
+	s.registryMu.Unlock()
+	s.notifyToolsChanged(ctx)
+}
+
+// toolRegistry returns the shared registry, building it on first use. The
+// registry is published only once it holds every exposed tool.
+func (s *Service) toolRegistry() *serverproto.Registry {
+	s.registryMu.Lock()
+	defer s.registryMu.Unlock()
+	s.mu.RLock()
+	registry := s.registry
+	s.mu.RUnlock()
+	if registry != nil {
+		return registry
+	}
+	registry = serverproto.NewRegistry()
+	s.syncTools(registry)
+	s.mu.Lock()
+	s.registry = registry
+	s.mu.Unlock()
+	return registry
+}
+
+// syncTools adds or replaces every exposed tool in registry and drops tools
+// that are no longer backed by an action or exposed. Callers hold registryMu.
+func (s *Service) syncTools(registry *serverproto.Registry) {
+	current := make(map[string]bool)
+	for _, entry := range s.ExposedTools() {
+		registry.RegisterTool(entry)
+		current[entry.Metadata.Name] = true
+	}
+	var stale []string
+	for _, entry := range registry.ToolRegistry.Values() {
+		if !current[entry.Metadata.Name] {
+			stale = append(stale, entry.Metadata.Name)
+		}
+	}
+	for _, name := range stale {
+		registry.ToolRegistry.Delete(name)
+	}
+}
+
+// notifyToolsChanged sends notifications/tools/list_changed to every session.
+// Sessions whose notifier fails are assumed to be gone and are dropped as
+// well.
+func (s *Service) notifyToolsChanged(ctx context.Context) {
+	s.mu.RLock()
+	sessions := make([]transport.Notifier, 0, len(s.sessions))
+	for notifier := range s.sessions {
+		sessions = append(sessions, notifier)
+	}
+	s.mu.RUnlock()
+	for _, notifier := range sessions {
+		notification := &jsonrpc.Notification{Jsonrpc: jsonrpc.Version, Method: tool.MethodNotificationToolsListChanged}
+		if err := notifier.Notify(ctx, notification); err != nil {
+			s.removeSession(notifier)
+		}
+	}
+}
+
+func (s *Service) addSession(notifier transport.Notifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions == nil {
+		s.sessions = make(map[transport.Notifier]struct{})
+	}
+	s.sessions[notifier] = struct{}{}
+}
+
+func (s *Service) removeSession(notifier transport.Notifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, notifier)
 }
@@ -0,0 +1,354 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/fluxor-mcp/mcp/config"
+	"github.com/viant/fluxor-mcp/mcp/tool"
+	"github.com/viant/jsonrpc"
+	"github.com/viant/mcp"
+	authschema "github.com/viant/mcp-protocol/authorization"
+	mcpschema "github.com/viant/mcp-protocol/schema"
+	serverproto "github.com/viant/mcp-protocol/server"
+)
+
+// recordingNotifier captures notifications sent to a session.
+type recordingNotifier struct {
+	mu      sync.Mutex
+	methods []string
+	params  []json.RawMessage
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, notification *jsonrpc.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.methods = append(r.methods, notification.Method)
+	r.params = append(r.params, notification.Params)
+	return nil
+}
+
+// TestServiceNewHandler_SharedRegistry verifies that all sessions share one
+// live registry and are notified when the tool set changes.
+func TestServiceNewHandler_SharedRegistry(t *testing.T) {
+	ctx := context.Background()
+	svc, err := New(ctx)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+
+	first, second := &recordingNotifier{}, &recordingNotifier{}
+	h1, err := svc.NewHandler(ctx, first, nil, nil)
+	assert.NoError(t, err)
+	h2, err := svc.NewHandler(ctx, second, nil, nil)
+	assert.NoError(t, err)
+
+	r1 := h1.(*serverHandler).Registry
+	r2 := h2.(*serverHandler).Registry
+	assert.Same(t, r1, r2)
+	assert.EqualValues(t, len(svc.Tools()), r1.ToolRegistry.Size())
+
+	svc.RefreshTools(ctx)
+	for _, notifier := range []*recordingNotifier{first, second} {
+		assert.EqualValues(t, []string{tool.MethodNotificationToolsListChanged}, notifier.methods)
+	}
+}
+
+// TestServerHandler_CallToolProgress verifies that execution stages are sent
+// as notifications/progress on the caller supplied token.
+func TestServerHandler_CallToolProgress(t *testing.T) {
+	ctx := context.Background()
+	svc, err := New(ctx)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	notifier := &recordingNotifier{}
+	handler, err := svc.NewHandler(ctx, notifier, nil, nil)
+	assert.NoError(t, err)
+	impl := handler.(*serverHandler)
+	impl.RegisterTool(&serverproto.ToolEntry{
+		Metadata: mcpschema.Tool{Name: "test-progress"},
+		Handler: func(ctx context.Context, request *mcpschema.CallToolRequest) (*mcpschema.CallToolResult, *jsonrpc.Error) {
+			reportProgress(ctx, ProgressStarted, request.Params.Name, "")
+			reportProgress(ctx, ProgressCompleted, request.Params.Name, "")
+			return &mcpschema.CallToolResult{}, nil
+		},
+	})
+
+	request := &jsonrpc.TypedRequest[*mcpschema.CallToolRequest]{Request: &mcpschema.CallToolRequest{Params: mcpschema.CallToolRequestParams{Name: "test-progress"}}}
+	_, rpcErr := impl.CallTool(ctx, request)
+	assert.Nil(t, rpcErr)
+	assert.Empty(t, notifier.methods, "no token – no progress")
+
+	tokenCtx := context.WithValue(ctx, mcpschema.TokenProgressContextKey, mcpschema.ProgressToken(7))
+	_, rpcErr = impl.CallTool(tokenCtx, request)
+	assert.Nil(t, rpcErr)
+	assert.EqualValues(t, []string{mcpschema.MethodNotificationProgress, mcpschema.MethodNotificationProgress}, notifier.methods)
+	var messages []string
+	for i, data := range notifier.params {
+		params := &mcpschema.ProgressNotificationParams{}
+		assert.NoError(t, json.Unmarshal(data, params))
+		assert.EqualValues(t, 7, params.ProgressToken)
+		assert.EqualValues(t, i+1, params.Progress)
+		messages = append(messages, *params.Message)
+	}
+	assert.EqualValues(t, []string{"test-progress started", "test-progress completed"}, messages)
+}
+
+// TestServiceNewHandler_SessionClosed verifies that a session is no longer
+// notified once its connection context is done.
+func TestServiceNewHandler_SessionClosed(t *testing.T) {
+	ctx := context.Background()
+	svc, err := New(ctx)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	connCtx, cancel := context.WithCancel(ctx)
+	closed, open := &recordingNotifier{}, &recordingNotifier{}
+	_, err = svc.NewHandler(connCtx, closed, nil, nil)
+	assert.NoError(t, err)
+	_, err = svc.NewHandler(ctx, open, nil, nil)
+	assert.NoError(t, err)
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		svc.mu.RLock()
+		defer svc.mu.RUnlock()
+		_, ok := svc.sessions[closed]
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+
+	svc.RefreshTools(ctx)
+	assert.Empty(t, closed.methods)
+	assert.EqualValues(t, []string{tool.MethodNotificationToolsListChanged}, open.methods)
+}
+
+// TestService_ToolRegistryConcurrent verifies that concurrent first use never
+// observes a registry that has not been filled yet.
+func TestService_ToolRegistryConcurrent(t *testing.T) {
+	ctx := context.Background()
+	svc, err := New(ctx)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	expected := len(svc.ExposedTools())
+	var wg sync.WaitGroup
+	sizes := make([]int, 8)
+	for i := range sizes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sizes[i] = svc.toolRegistry().ToolRegistry.Size()
+		}(i)
+	}
+	wg.Wait()
+	for _, size := range sizes {
+		assert.EqualValues(t, expected, size)
+	}
+}
+
+// TestService_RefreshToolsConcurrent verifies that concurrent refreshes leave
+// the registry holding exactly the exposed tools.
+func TestService_RefreshToolsConcurrent(t *testing.T) {
+	ctx := context.Background()
+	svc, err := New(ctx)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	registry := svc.toolRegistry()
+	registry.RegisterTool(&serverproto.ToolEntry{Metadata: mcpschema.Tool{Name: "stale-tool"}})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			svc.RefreshTools(ctx)
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, len(svc.ExposedTools()), registry.ToolRegistry.Size())
+	_, ok := registry.ToolRegistry.Get("stale-tool")
+	assert.False(t, ok)
+}
+
+// upstreamHelperEnv makes the test binary act as a stdio MCP server.
+const upstreamHelperEnv = "MCP_UPSTREAM_HELPER"
+
+// TestHelperUpstream is not a test: run with upstreamHelperEnv set it acts as
+// a stdio MCP server with a single whoami tool returning the caller token
+// found in _meta.authorization.token and the server process ID.
+func TestHelperUpstream(t *testing.T) {
+	if os.Getenv(upstreamHelperEnv) == "" {
+		return
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params struct {
+				Meta struct {
+					Authorization struct {
+						Token string `json:"token"`
+					} `json:"authorization"`
+				} `json:"_meta"`
+			} `json:"params"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &request) != nil || len(request.ID) == 0 {
+			continue
+		}
+		var result string
+		switch request.Method {
+		case mcpschema.MethodInitialize:
+			result = `{"protocolVersion":"2025-03-26","capabilities":{"tools":{}},"serverInfo":{"name":"helper","version":"v1"}}`
+		case mcpschema.MethodToolsList:
+			result = `{"tools":[{"name":"whoami","inputSchema":{"type":"object","properties":{}}}]}`
+		case mcpschema.MethodToolsCall:
+			text, _ := json.Marshal(fmt.Sprintf("%s@%d", request.Params.Meta.Authorization.Token, os.Getpid()))
+			result = fmt.Sprintf(`{"content":[{"type":"text","text":%s}]}`, text)
+		default:
+			result = `{}`
+		}
+		fmt.Printf("{\"jsonrpc\":\"2.0\",\"id\":%s,\"result\":%s}\n", request.ID, result)
+	}
+	os.Exit(0)
+}
+
+// TestServerHandler_CallToolUpstream verifies that a tool imported with
+// credential forwarding and isolation and called through serve reaches the
+// upstream server with the caller token, on a connection of the caller's
+// session.
+func TestServerHandler_CallToolUpstream(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv(upstreamHelperEnv, "1")
+	svc, err := New(ctx, WithConfig(&config.Config{MCP: helperUpstream()}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer svc.Shutdown(ctx)
+	whoami, err := svc.WorkflowService().Actions().Lookup("helper").Method("whoami")
+	if !assert.NoError(t, err) {
+		return
+	}
+	svc.runtime = &fakeRuntime{run: func(ctx context.Context) (interface{}, error) {
+		var output interface{}
+		err := whoami(ctx, map[string]interface{}{}, &output)
+		return output, err
+	}}
+
+	call := func(handler serverproto.Handler, caller string) (string, string) {
+		return callWhoami(t, ctx, handler, caller)
+	}
+	first, err := svc.NewHandler(ctx, nil, nil, nil)
+	assert.NoError(t, err)
+	second, err := svc.NewHandler(ctx, nil, nil, nil)
+	assert.NoError(t, err)
+
+	token, firstPID := call(first, "alice")
+	assert.EqualValues(t, "alice", token, "caller token forwarded")
+	token, again := call(first, "alice")
+	assert.EqualValues(t, "alice", token)
+	assert.EqualValues(t, firstPID, again, "session keeps its connection")
+	token, secondPID := call(second, "bob")
+	assert.EqualValues(t, "bob", token)
+	assert.NotEqual(t, firstPID, secondPID, "sessions are isolated")
+
+	shared, err := svc.ExecuteTool(ctx, "helper-whoami", map[string]interface{}{}, time.Second)
+	if assert.NoError(t, err) {
+		_, sharedPID, _ := strings.Cut(strings.Trim(fmt.Sprint(shared), `"`), "@")
+		assert.NotEmpty(t, sharedPID)
+		assert.NotContains(t, []string{firstPID, secondPID}, sharedPID, "library calls use the shared connection")
+	}
+}
+
+// helperUpstream configures the TestHelperUpstream server as an imported
+// stdio server with credential forwarding and per-session isolation.
+func helperUpstream() *config.Group[*config.MCPClient] {
+	options := &mcp.ClientOptions{Name: "helper", Version: "v1"}
+	options.Transport.Type = "stdio"
+	options.Transport.Command = os.Args[0]
+	options.Transport.Arguments = []string{"-test.run=TestHelperUpstream"}
+	return &config.Group[*config.MCPClient]{Items: []*config.MCPClient{{
+		ClientOptions: options,
+		ForwardAuth:   &config.ForwardAuth{Mode: config.ForwardPassthrough},
+		Isolation:     &config.Isolation{},
+	}}}
+}
+
+// callWhoami calls helper-whoami through handler as caller and returns the
+// token and pid seen by the upstream server.
+func callWhoami(t *testing.T, ctx context.Context, handler serverproto.Handler, caller string) (string, string) {
+	callerCtx := context.WithValue(ctx, authschema.TokenKey, &authschema.Token{Token: "Bearer " + caller})
+	request := &jsonrpc.TypedRequest[*mcpschema.CallToolRequest]{Request: &mcpschema.CallToolRequest{Params: mcpschema.CallToolRequestParams{Name: "helper-whoami"}}}
+	result, rpcErr := handler.(*serverHandler).CallTool(callerCtx, request)
+	if !assert.Nil(t, rpcErr) || !assert.Len(t, result.Content, 1) {
+		return "", ""
+	}
+	assert.Nil(t, result.IsError, result.Content[0].Text)
+	token, pid, _ := strings.Cut(strings.Trim(result.Content[0].Text, `"`), "@")
+	return token, pid
+}
+
+// TestServerHandler_CallToolRuntime verifies, on the Fluxor runtime, that the
+// context values set by CallTool reach the scheduled action: the caller token
+// and the session's isolated connection reach an upstream tool, and the
+// progress reporter reaches a workflow.
+func TestServerHandler_CallToolRuntime(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv(upstreamHelperEnv, "1")
+	dir := t.TempDir()
+	definition := `pipeline:
+  run:
+    action: system/exec:execute
+    input:
+      commands: ["sleep 0.3"]
+`
+	if !assert.NoError(t, os.WriteFile(filepath.Join(dir, "steps.yaml"), []byte(definition), 0644)) {
+		return
+	}
+	svc, err := New(ctx, WithConfig(&config.Config{MCP: helperUpstream(), Workflows: []string{dir}}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer svc.Shutdown(ctx)
+
+	notifier := &recordingNotifier{}
+	first, err := svc.NewHandler(ctx, notifier, nil, nil)
+	assert.NoError(t, err)
+	second, err := svc.NewHandler(ctx, nil, nil, nil)
+	assert.NoError(t, err)
+
+	token, firstPID := callWhoami(t, ctx, first, "alice")
+	assert.EqualValues(t, "alice", token, "caller token forwarded")
+	token, secondPID := callWhoami(t, ctx, second, "bob")
+	assert.EqualValues(t, "bob", token)
+	assert.NotEmpty(t, firstPID)
+	assert.NotEqual(t, firstPID, secondPID, "sessions use their own connection")
+
+	tokenCtx := context.WithValue(ctx, mcpschema.TokenProgressContextKey, mcpschema.ProgressToken(1))
+	request := &jsonrpc.TypedRequest[*mcpschema.CallToolRequest]{Request: &mcpschema.CallToolRequest{Params: mcpschema.CallToolRequestParams{Name: "workflow-steps"}}}
+	result, rpcErr := first.(*serverHandler).CallTool(tokenCtx, request)
+	if !assert.Nil(t, rpcErr) {
+		return
+	}
+	assert.Nil(t, result.IsError)
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	var messages []string
+	for _, data := range notifier.params {
+		params := &mcpschema.ProgressNotificationParams{}
+		if json.Unmarshal(data, params) == nil && params.Message != nil {
+			messages = append(messages, *params.Message)
+		}
+	}
+	assert.Regexp(t, `task \S*run started`, strings.Join(messages, "\n"), "task progress reported from the workflow")
+}
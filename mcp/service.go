@@ -3,14 +3,20 @@ package mcp
 import (
 	"context"
 	"github.com/viant/fluxor"
+	"github.com/viant/fluxor-mcp/mcp/auth"
 	"github.com/viant/fluxor-mcp/mcp/config"
+	"github.com/viant/fluxor-mcp/mcp/job"
+	"github.com/viant/fluxor-mcp/mcp/tool"
+	"github.com/viant/fluxor-mcp/mcp/upstream"
 	"github.com/viant/fluxor/model/types"
+	"github.com/viant/jsonrpc/transport"
 	"github.com/viant/mcp"
 	"github.com/viant/x"
 	"sync"
 	"sync/atomic"
 
 	protocolclient "github.com/viant/mcp-protocol/client"
+	serverproto "github.com/viant/mcp-protocol/server"
 )
 
 // Service bundles configuration, a Fluxor Workflow engine and auxiliary state
@@ -20,10 +26,44 @@ import (
 type Service struct {
 	Workflow
 	started         int32
+	closeOnce       sync.Once
 	clientHandler   protocolclient.Handler
 	config          *config.Config
 	mcpErrorHandler func(config *mcp.ClientOptions, err error) error
 
+	// registry is the live tool registry shared by all served sessions.
+	registry *serverproto.Registry
+	// registryMu serialises building and synchronising the registry.
+	registryMu sync.Mutex
+	// sessions holds notifiers of connected sessions for list_changed fan-out.
+	sessions map[transport.Notifier]struct{}
+	// jobs tracks tools started asynchronously (nil – in memory).
+	jobs job.Store
+	// limits holds concurrency semaphores keyed by tool rule (nil – global).
+	limits map[*config.ToolRule]chan struct{}
+	// hints holds behaviour hints of service instances, e.g. built-in ones.
+	hints map[types.Service]*tool.Annotations
+	// naming maps served tool names to canonical ones (nil – unchanged).
+	naming *tool.Naming
+	// upstreams holds the connections to imported MCP servers.
+	upstreams []*upstream.Client
+	// proxies holds the tool services of imported MCP servers.
+	proxies []*tool.Proxy
+	// actionsMu serialises registration of imported services.
+	actionsMu sync.Mutex
+	// toolCache persists tool lists of imported servers (nil – disabled).
+	toolCache *tool.ManifestCache
+	// tokenExchanger exchanges caller tokens for imported servers.
+	tokenExchanger auth.ExchangeFunc
+	// tokenVerifier verifies caller tokens (nil – callers are unverified).
+	tokenVerifier auth.VerifyFunc
+	// isolated holds per-caller connection pools keyed by proxy service name.
+	isolated map[string]*isolation
+	// runtime schedules tool executions (nil – Workflow.Runtime).
+	runtime executionRuntime
+	// sessionSeq numbers served sessions.
+	sessionSeq uint64
+
 	// guard concurrent modifications.
 	mu sync.RWMutex
 }
@@ -48,6 +88,10 @@ func (s *Service) WorkflowService() *fluxor.Service { return s.Workflow.Service
 // construction time.  Callers must treat the returned object as read-only.
 func (s *Service) Config() *config.Config { return s.config }
 
+// ToolNames returns the served → canonical tool name table; it is empty
+// unless tools.naming is configured.
+func (s *Service) ToolNames() map[string]string { return s.naming.Mapping() }
+
 // Option modifies a service instance before it is initialised. Users can pass
 // an arbitrary number of options to New.
 type Option func(*Service)
@@ -84,6 +128,33 @@ func WithClientHandler(clientHandler protocolclient.Handler) Option {
 	}
 }
 
+// WithTokenExchanger sets the function exchanging caller tokens for imported
+// servers configured with forwardAuth mode "exchange" and no exchange
+// endpoint.
+func WithTokenExchanger(exchanger auth.ExchangeFunc) Option {
+	return func(s *Service) {
+		s.tokenExchanger = exchanger
+	}
+}
+
+// WithTokenVerifier sets the function verifying caller tokens and returning
+// the caller identity. Imported servers isolated by user require it, as an
+// unverified token may name any identity.
+func WithTokenVerifier(verifier auth.VerifyFunc) Option {
+	return func(s *Service) {
+		s.tokenVerifier = verifier
+	}
+}
+
+// WithJobStore sets the store holding asynchronous jobs; by default jobs are
+// kept in memory. Use job.NewExecutionStore with the Fluxor execution DAO to
+// keep them across restarts.
+func WithJobStore(store job.Store) Option {
+	return func(s *Service) {
+		s.jobs = store
+	}
+}
+
 func WithMcpErrorHandler(handler func(config *mcp.ClientOptions, err error) error) Option {
 	return func(s *Service) {
 		s.mcpErrorHandler = handler
@@ -124,9 +195,16 @@ func (s *Service) Start(ctx context.Context) error {
 	return s.Workflow.Runtime.Start(ctx)
 }
 
-// Shutdown terminates the Fluxor runtime. Additional invocations after the
-// first successful call have no effect.
+// Shutdown closes connections to imported MCP servers and terminates the
+// Fluxor runtime. Additional invocations after the first successful call have
+// no effect.
 func (s *Service) Shutdown(ctx context.Context) error {
+	s.closeOnce.Do(func() {
+		// connections are opened while the service is built, whether or not
+		// Start is called
+		s.closeUpstreams()
+		s.closeIsolation()
+	})
 	if !atomic.CompareAndSwapInt32(&s.started, 1, 2) {
 		return nil
 	}
@@ -3,12 +3,12 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/viant/fluxor-mcp/internal/conv"
-	"github.com/viant/fluxor-mcp/mcp/matcher"
+	"github.com/viant/fluxor-mcp/mcp/job"
 	"github.com/viant/fluxor-mcp/mcp/tool"
 	"github.com/viant/fluxor-mcp/mcp/tool/conversion"
 	"github.com/viant/fluxor/model/types"
@@ -30,7 +30,7 @@ func (s *Service) Tools() serverproto.Tools {
 				continue
 			}
 			toolName := tool.NewName(name, method.Name)
-			aTool, err := s.LookupTool(toolName.String())
+			aTool, err := s.lookupTool(toolName.String())
 			if err != nil {
 				continue
 			}
@@ -41,38 +41,64 @@ func (s *Service) Tools() serverproto.Tools {
 }
 
 // MatchTools returns a subset of Tools() whose names match the supplied
-// pattern. The matching rules follow the same convention used by builtin
-// action auto-loading (see builtins.go):
+// pattern. Patterns follow matcher.Match, applied to the slash separated
+// tool name (service path and method, e.g. "system/exec/execute"):
 //  1. "*" – returns all registered tools (equivalent to Tools()).
-//  2. Prefix   – when the pattern ends with "/" it is treated as a service
-//     prefix. All tool names whose service component starts with that prefix
-//     are returned. Example: "system/" matches "system_storage-clean".
-//  3. Exact    – any other pattern is compared against the full tool name
-//     (service and method in canonical form). The service part may use
-//     slashes or underscores; internally it is normalised so that callers
-//     can use the more familiar slash-separated syntax.
+//  2. Prefix   – "system/" matches every tool of services under system,
+//     "system/exec" every method of system/exec.
+//  3. Glob     – "printer/*", "system/*/execute", "system/**".
+//  4. Regex    – "re:^system/(exec|patch)/".
+//  5. Negation – "!system/exec" matches every tool except system/exec ones.
+//
+// Canonical names ("system_exec-execute") are accepted as well and normalised
+// to slash form.
 //
 // The function never returns nil – callers can range over the result safely.
 func (s *Service) MatchTools(pattern string) serverproto.Tools {
-	// Normalise pattern: replace service separators and ensure prefix patterns
-	// map to canonical dash after service part.
-	norm := strings.ReplaceAll(pattern, "/", "_")
-
-	if strings.HasSuffix(pattern, "/") { // service prefix pattern
-		norm = strings.TrimSuffix(norm, "_") + "-"
-	}
 	matched := make(serverproto.Tools, 0)
 	for _, t := range s.Tools() {
-		if matcher.Match(norm, t.Metadata.Name) {
+		if tool.Match(pattern, s.canonicalToolName(t.Metadata.Name)) {
 			matched = append(matched, t)
 		}
 	}
 	return matched
 }
 
+// ExposedTools returns the subset of Tools() offered by the MCP server, as
+// selected by the expose section of the configuration.
+func (s *Service) ExposedTools() serverproto.Tools {
+	exposed := make(serverproto.Tools, 0)
+	for _, t := range s.Tools() {
+		if s.IsExposed(t.Metadata.Name) {
+			exposed = append(exposed, t)
+		}
+	}
+	return exposed
+}
+
+// IsExposed reports whether the named tool is offered by the MCP server.
+func (s *Service) IsExposed(name string) bool {
+	expose := s.config.Expose
+	if expose == nil {
+		return true
+	}
+	name = s.canonicalToolName(name)
+	if len(expose.Include) > 0 && !tool.MatchAny(expose.Include, name) {
+		return false
+	}
+	return !tool.MatchAny(expose.Exclude, name)
+}
+
 // LookupTool returns a pointer to the internal entry with the given name
-// and a bool indicating presence. Internal helper for CLI inspection.
+// and a bool indicating presence. Internal helper for CLI inspection. Both
+// sanitized and canonical names are accepted; the entry carries the
+// sanitized name.
 func (s *Service) LookupTool(name string) (*serverproto.ToolEntry, error) {
+	return s.lookupTool(s.canonicalToolName(name))
+}
+
+// lookupTool builds the entry of a tool identified by its canonical name.
+func (s *Service) lookupTool(name string) (*serverproto.ToolEntry, error) {
 	toolName := tool.Name(name)
 	actions := s.Workflow.Service.Actions()
 	service := actions.Lookup(toolName.Service())
@@ -85,7 +111,7 @@ func (s *Service) LookupTool(name string) (*serverproto.ToolEntry, error) {
 				return nil, fmt.Errorf("unknown tool: %v", toolName)
 			}
 			sig := &types.Signature{
-				Name:        name,
+				Name:        s.naming.Name(name),
 				Input:       method.Input,
 				Output:      method.Output,
 				Description: method.Description,
@@ -94,30 +120,20 @@ func (s *Service) LookupTool(name string) (*serverproto.ToolEntry, error) {
 			if toolEntry.Metadata, err = conversion.BuildSchema(sig); err != nil {
 				return nil, err
 			}
+			toolEntry.Metadata.Annotations = s.toolAnnotations(name, service, toolMethod).Schema()
+			outputSchema := toolEntry.Metadata.OutputSchema
+			jobTool := toolName.Service() == job.ServiceName
 			toolEntry.Handler = func(ctx context.Context, request *mcpschema.CallToolRequest) (*mcpschema.CallToolResult, *jsonrpc.Error) {
-				output, err := s.ExecuteTool(ctx, request.Params.Name, request.Params.Arguments, 15*time.Minute)
-				res := &mcpschema.CallToolResult{}
-				if err != nil {
-					res.IsError = conv.Pointer[bool](true)
-					res.Content = append(res.Content, mcpschema.CallToolResultContentElem{
-						Text: err.Error(),
-					})
-					return res, nil
+				if jobTool {
+					if err := s.checkJobCall(ctx, name, toolMethod, request.Params.Arguments); err != nil {
+						return errorResult(err), nil
+					}
 				}
-
-				var data []byte
-				switch actual := output.(type) {
-				case string:
-					data = []byte(actual)
-				case []byte:
-					data = actual
-				default:
-					data, _ = json.Marshal(output)
+				output, err := s.ExecuteTool(ctx, request.Params.Name, request.Params.Arguments, 0)
+				if err != nil {
+					return errorResult(err), nil
 				}
-				res.Content = append(res.Content, mcpschema.CallToolResultContentElem{
-					Text: string(data),
-				})
-				return res, nil
+				return s.toolResult(output, outputSchema), nil
 			}
 			return &toolEntry, nil
 		}
@@ -125,8 +141,117 @@ func (s *Service) LookupTool(name string) (*serverproto.ToolEntry, error) {
 	return nil, fmt.Errorf("unknown tool: %v", toolName)
 }
 
+// canonicalToolName resolves a sanitized tool name to its canonical form.
+func (s *Service) canonicalToolName(name string) string {
+	canonical, _ := s.naming.Canonical(name)
+	return canonical
+}
+
+// indexToolNames issues served names for every registered tool so that they
+// resolve before the tool list is built. It runs whenever the tool set changes.
+func (s *Service) indexToolNames() {
+	if s.naming == nil {
+		return
+	}
+	actions := s.Workflow.Service.Actions()
+	for _, name := range actions.Services() {
+		for _, method := range actions.Lookup(name).Methods() {
+			if !method.Internal {
+				s.naming.Name(tool.NewName(name, method.Name).String())
+			}
+		}
+	}
+}
+
+// toolResult converts an action output into a call result. Object outputs are
+// returned as structuredContent with a JSON text mirror for clients that do
+// not read structured content; other outputs are returned as text only.
+func (s *Service) toolResult(output interface{}, outputSchema *mcpschema.ToolOutputSchema) *mcpschema.CallToolResult {
+	res := &mcpschema.CallToolResult{}
+	var data []byte
+	switch actual := output.(type) {
+	case string:
+		data = []byte(actual)
+	case []byte:
+		data = actual
+	default:
+		data, _ = json.Marshal(output)
+		if structured := structuredOutput(data); structured != nil {
+			if s.config.Tools != nil && s.config.Tools.ValidateOutput {
+				if err := conversion.ValidateOutput(outputSchema, structured); err != nil {
+					return errorResult(fmt.Errorf("output does not match outputSchema: %w", err))
+				}
+			}
+			res.StructuredContent = structured
+		}
+	}
+	res.Content = append(res.Content, mcpschema.CallToolResultContentElem{
+		Type: "text",
+		Text: string(data),
+	})
+	return res
+}
+
+// structuredOutput returns the JSON object encoded in data, or nil for any
+// other JSON value.
+func structuredOutput(data []byte) map[string]interface{} {
+	if len(data) == 0 || data[0] != '{' {
+		return nil
+	}
+	var structured map[string]interface{}
+	if err := json.Unmarshal(data, &structured); err != nil {
+		return nil
+	}
+	return structured
+}
+
+// errorResult converts an execution error into a tool error result. An
+// ExecutionError is returned as structuredContent with a JSON text mirror.
+func errorResult(err error) *mcpschema.CallToolResult {
+	res := &mcpschema.CallToolResult{IsError: conv.Pointer[bool](true)}
+	text := err.Error()
+	var execErr *ExecutionError
+	if errors.As(err, &execErr) {
+		if data, mErr := json.Marshal(execErr); mErr == nil {
+			res.StructuredContent = structuredOutput(data)
+			text = string(data)
+		}
+	}
+	res.Content = append(res.Content, mcpschema.CallToolResultContentElem{
+		Type: "text",
+		Text: text,
+	})
+	return res
+}
+
 // ExecuteTool invokes a registered fluxor action with the supplied arguments.
+// A zero timeout uses the timeout configured for the tool in the tools
+// section; configured default arguments and concurrency limits apply as well.
+// Failures are reported as *ExecutionError.
 func (s *Service) ExecuteTool(ctx context.Context, name string, args map[string]interface{}, timeout time.Duration) (interface{}, error) {
+	name = s.canonicalToolName(name)
+	policy := s.toolPolicy(name)
+	if timeout == 0 {
+		timeout = policy.timeout
+	}
+	release, err := policy.acquire(ctx)
+	if err != nil {
+		return nil, newExecutionError(name, err)
+	}
+	defer release()
+	anExec, waitFn, err := s.scheduleTool(ctx, name, policy.arguments(args))
+	if err != nil {
+		reportProgress(ctx, ProgressFailed, name, err.Error())
+		return nil, err
+	}
+	reportProgress(ctx, ProgressScheduled, name, anExec.ID)
+	reportProgress(ctx, ProgressStarted, name, "")
+	return s.awaitTool(ctx, name, anExec, waitFn, timeout)
+}
+
+// scheduleTool validates the tool name and schedules an at-hoc execution of
+// the underlying action. The returned function waits for its completion.
+func (s *Service) scheduleTool(ctx context.Context, name string, args map[string]interface{}) (*execution.Execution, func(time.Duration) (*execution.Execution, error), error) {
 	toolName := tool.Name(name)
 
 	// Early validation: ensure service and method exist so that callers get a
@@ -135,41 +260,126 @@ func (s *Service) ExecuteTool(ctx context.Context, name string, args map[string]
 	actions := s.Workflow.Service.Actions()
 	svc := actions.Lookup(toolName.Service())
 	if svc == nil {
-		return nil, fmt.Errorf("unknown service: %s", toolName.Service())
+		return nil, nil, &ExecutionError{Code: ErrorCodeUnknownTool, Tool: name, Message: fmt.Sprintf("unknown service: %s", toolName.Service())}
 	}
 
-	found := false
+	var method *types.Signature
 	methodName := toolName.Method()
-	for _, sig := range svc.Methods() {
-		if sig.Name == methodName {
-			found = true
+	methods := svc.Methods()
+	for i := range methods {
+		if methods[i].Name == methodName {
+			method = &methods[i]
 			break
 		}
 	}
-	if !found {
-		return nil, fmt.Errorf("unknown method: %s in service %s", toolName.Method(), toolName.Service())
+	if method == nil {
+		return nil, nil, &ExecutionError{Code: ErrorCodeUnknownTool, Tool: name, Message: fmt.Sprintf("unknown method: %s in service %s", toolName.Method(), toolName.Service())}
+	}
+	if presetter, ok := svc.(tool.Presetter); ok {
+		args = presetter.WithPresets(methodName, args)
+	}
+	var document map[string]interface{}
+	if documenter, ok := svc.(tool.InputDocumenter); ok {
+		document = documenter.InputDocument(methodName)
+	}
+	if err := s.validateArguments(name, method, document, args); err != nil {
+		return nil, nil, err
 	}
 
 	exec, err := execution.NewAtHocExecution(toolName.Service(), toolName.Method(), args)
 	if err != nil {
-		return "", fmt.Errorf("failed to create at-hoc execution: %v", err)
+		return nil, nil, &ExecutionError{Code: ErrorCodeScheduleFailed, Tool: name, Message: fmt.Sprintf("failed to create at-hoc execution: %v", err), cause: err}
 	}
 
-	waitFn, err := s.Runtime.ScheduleExecution(ctx, exec)
+	waitFn, err := s.executionRuntime().ScheduleExecution(ctx, exec)
 	if err != nil {
-		return "", err
+		return nil, nil, &ExecutionError{Code: ErrorCodeScheduleFailed, Tool: name, Message: err.Error(), cause: err}
+	}
+	return exec, waitFn, nil
+}
+
+// validateArguments checks args against the input schema advertised for the
+// method – the upstream document, when given, of a proxied tool – so that
+// invalid calls fail before scheduling with field-level errors instead of
+// deep inside the action.
+func (s *Service) validateArguments(name string, method *types.Signature, document map[string]interface{}, args map[string]interface{}) error {
+	if tools := s.config.Tools; tools != nil && tools.SkipInputValidation {
+		return nil
+	}
+	var err error
+	switch {
+	case document != nil:
+		err = conversion.ValidateInputDocument(document, args)
+	case method.Input != nil:
+		metadata, buildErr := conversion.BuildSchema(&types.Signature{Name: s.naming.Name(name), Input: method.Input, Output: method.Output, Description: method.Description})
+		if buildErr != nil {
+			return nil // no advertised schema to validate against
+		}
+		err = conversion.ValidateInput(&metadata.InputSchema, args)
 	}
+	if err == nil {
+		return nil
+	}
+	ret := &ExecutionError{Code: ErrorCodeInvalidArguments, Tool: name, Message: err.Error(), cause: err}
+	var validationErr *conversion.ValidationError
+	if errors.As(err, &validationErr) {
+		ret.Errors = validationErr.Errors
+	}
+	return ret
+}
 
-	// expected until the background processor persists the execution.
-	anExec, err := waitFn(timeout)
+// awaitTool waits for a scheduled execution and returns its output, reporting
+// the completed or failed stage. When ctx is cancelled (notifications/cancelled
+// or a dropped connection) waiting stops, but the Fluxor runtime cannot abort
+// the execution: the returned error says that it keeps running.
+func (s *Service) awaitTool(ctx context.Context, name string, anExec *execution.Execution, waitFn func(time.Duration) (*execution.Execution, error), timeout time.Duration) (interface{}, error) {
+	completed, err := waitExecution(ctx, waitFn, timeout)
 	if err != nil {
-		return "", err
+		if ctx.Err() != nil {
+			err = errors.Join(err, fmt.Errorf("execution %s keeps running: %w", anExec.ID, job.ErrNotCancellable))
+		}
+		reportProgress(ctx, ProgressFailed, name, err.Error())
+		return nil, newExecutionError(name, err)
 	}
+	anExec = completed
 
 	if anExec.Error != "" {
-		var errorMap = map[string]interface{}{"error": anExec.Error}
-		errorResponse, _ := json.Marshal(errorMap)
-		return string(errorResponse), nil
+		reportProgress(ctx, ProgressFailed, failedTask(name, anExec), anExec.Error)
+		return nil, failedExecutionError(name, anExec)
 	}
+	reportProgress(ctx, ProgressCompleted, name, "")
 	return anExec.Output, nil
 }
+
+// waitExecution runs waitFn until it returns or ctx is done, whichever comes
+// first. A wait failing once timeout elapsed is reported as
+// context.DeadlineExceeded, as the runtime's own timeout error is untyped.
+func waitExecution(ctx context.Context, waitFn func(time.Duration) (*execution.Execution, error), timeout time.Duration) (*execution.Execution, error) {
+	type waitResult struct {
+		anExec *execution.Execution
+		err    error
+	}
+	done := make(chan waitResult, 1)
+	started := time.Now()
+	go func() {
+		anExec, err := waitFn(timeout)
+		done <- waitResult{anExec: anExec, err: err}
+	}()
+	select {
+	case result := <-done:
+		if result.err != nil && timeout > 0 && time.Since(started) >= timeout && !errors.Is(result.err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %w", context.DeadlineExceeded, result.err)
+		}
+		return result.anExec, result.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("execution cancelled: %w", context.Cause(ctx))
+	}
+}
+
+// failedTask qualifies the tool name with the failing task when known.
+func failedTask(name string, anExec *execution.Execution) string {
+	if anExec.TaskID == "" {
+		return name
+	}
+	return name + "/" + anExec.TaskID
+}
@@ -0,0 +1,34 @@
+package tool
+
+import (
+	"github.com/viant/fluxor-mcp/mcp/config"
+	mcpschema "github.com/viant/mcp-protocol/schema"
+)
+
+// Annotations are behaviour hints advertised with a tool (see
+// config.Annotations).
+type Annotations = config.Annotations
+
+// Annotator is implemented by Fluxor services that describe the behaviour of
+// their methods.
+type Annotator interface {
+	// Annotations returns the hints of a method or nil.
+	Annotations(method string) *Annotations
+}
+
+// AnnotationsFromSchema converts MCP tool annotations.
+func AnnotationsFromSchema(annotations *mcpschema.ToolAnnotations) *Annotations {
+	if annotations == nil {
+		return nil
+	}
+	ret := &Annotations{
+		ReadOnly:    annotations.ReadOnlyHint,
+		Destructive: annotations.DestructiveHint,
+		Idempotent:  annotations.IdempotentHint,
+		OpenWorld:   annotations.OpenWorldHint,
+	}
+	if annotations.Title != nil {
+		ret.Title = *annotations.Title
+	}
+	return ret
+}
@@ -0,0 +1,130 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/viant/fluxor-mcp/mcp/config"
+)
+
+// ErrCircuitOpen is returned without calling the upstream while its circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Default circuit breaker settings.
+const (
+	DefaultBreakerFailureThreshold = 5
+	DefaultBreakerOpen             = 30 * time.Second
+)
+
+// BreakerPolicy configures a circuit breaker (see config.BreakerPolicy).
+type BreakerPolicy = config.BreakerPolicy
+
+// Circuit states reported by CircuitState.
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+// CircuitState is a snapshot of a circuit breaker.
+type CircuitState struct {
+	State     string    `json:"state"`
+	Failures  int       `json:"failures"`
+	Threshold int       `json:"threshold"`
+	LastError string    `json:"lastError,omitempty"`
+	OpenUntil time.Time `json:"openUntil,omitempty"`
+}
+
+// String renders the state for error messages, e.g. "circuit closed, 2/5 failures".
+func (s CircuitState) String() string {
+	switch s.State {
+	case CircuitOpen:
+		return fmt.Sprintf("circuit open until %s", s.OpenUntil.Format(time.RFC3339))
+	case CircuitHalfOpen:
+		return "circuit half-open"
+	}
+	return fmt.Sprintf("circuit closed, %d/%d failures", s.Failures, s.Threshold)
+}
+
+type breaker struct {
+	threshold int
+	open      time.Duration
+	mu        sync.Mutex
+	failures  int
+	lastError string
+	openUntil time.Time
+	probing   bool
+	now       func() time.Time
+}
+
+func newBreaker(policy *BreakerPolicy) *breaker {
+	ret := &breaker{threshold: DefaultBreakerFailureThreshold, open: DefaultBreakerOpen, now: time.Now}
+	if policy.FailureThreshold > 0 {
+		ret.threshold = policy.FailureThreshold
+	}
+	if policy.OpenSec > 0 {
+		ret.open = time.Duration(policy.OpenSec) * time.Second
+	}
+	return ret
+}
+
+// allow returns ErrCircuitOpen, with the breaker state, while the circuit is
+// open or a half-open probe is already in flight.
+func (b *breaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return nil
+	}
+	if b.now().Before(b.openUntil) || b.probing {
+		return fmt.Errorf("%w after %d consecutive failures (retry after %s): %s",
+			ErrCircuitOpen, b.failures, b.openUntil.Format(time.RFC3339), b.lastError)
+	}
+	b.probing = true
+	return nil
+}
+
+// record updates the breaker with the error returned by an upstream call.
+// Responses, including JSON-RPC errors other than internal ones, close the
+// circuit; cancelled calls leave it unchanged.
+func (b *breaker) record(err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	switch class := errorClass(err); {
+	case errors.Is(err, context.Canceled), errors.Is(err, ErrCircuitOpen):
+	case class == "":
+		b.failures = 0
+		b.lastError = ""
+	default:
+		b.failures++
+		b.lastError = err.Error()
+		if b.failures >= b.threshold {
+			b.openUntil = b.now().Add(b.open)
+		}
+	}
+}
+
+func (b *breaker) state() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ret := CircuitState{State: CircuitClosed, Failures: b.failures, Threshold: b.threshold, LastError: b.lastError}
+	if b.failures >= b.threshold {
+		ret.State = CircuitHalfOpen
+		if b.now().Before(b.openUntil) {
+			ret.State = CircuitOpen
+			ret.OpenUntil = b.openUntil
+		}
+	}
+	return ret
+}
@@ -0,0 +1,68 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/viant/afs"
+	"github.com/viant/afs/url"
+)
+
+// DefaultManifestTTL is how long a cached manifest is used without listing
+// tools on startup.
+const DefaultManifestTTL = time.Hour
+
+// ManifestCache persists tool manifests of MCP servers as <name>.json files in
+// a directory or afs URL.
+type ManifestCache struct {
+	URL string
+	TTL time.Duration
+	fs  afs.Service
+}
+
+// NewManifestCache creates a cache rooted at URL; ttl <= 0 uses
+// DefaultManifestTTL.
+func NewManifestCache(URL string, ttl time.Duration) *ManifestCache {
+	if ttl <= 0 {
+		ttl = DefaultManifestTTL
+	}
+	return &ManifestCache{URL: URL, TTL: ttl, fs: afs.New()}
+}
+
+// Load returns the cached manifest of the named server, if any, and whether
+// it is younger than the TTL.
+func (c *ManifestCache) Load(ctx context.Context, name string) (*Manifest, bool, error) {
+	location := c.location(name)
+	if ok, _ := c.fs.Exists(ctx, location); !ok {
+		return nil, false, nil
+	}
+	manifest, err := LoadManifest(ctx, location)
+	if err != nil {
+		return nil, false, err
+	}
+	return manifest, time.Since(manifest.CreatedAt) < c.TTL, nil
+}
+
+// Store writes the manifest of the named server. The write is skipped when
+// the cached entry has the same ETag and is still fresh.
+func (c *ManifestCache) Store(ctx context.Context, name string, manifest *Manifest) error {
+	if cached, fresh, _ := c.Load(ctx, name); fresh && cached.ETag == manifest.ETag {
+		return nil
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if err = c.fs.Upload(ctx, c.location(name), 0644, bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("store tool manifest %q: %w", name, err)
+	}
+	return nil
+}
+
+func (c *ManifestCache) location(name string) string {
+	return url.Join(c.URL, strings.ReplaceAll(name, "/", "_")+".json")
+}
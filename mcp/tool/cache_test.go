@@ -0,0 +1,50 @@
+package tool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	mcpschema "github.com/viant/mcp-protocol/schema"
+)
+
+func TestManifestCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewManifestCache(t.TempDir(), time.Hour)
+	tools := []mcpschema.Tool{{Name: "query"}}
+
+	manifest, fresh, err := cache.Load(ctx, "analytics/prod")
+	assert.NoError(t, err)
+	assert.Nil(t, manifest, "nothing cached yet")
+	assert.False(t, fresh)
+
+	stored := &Manifest{Tools: tools, ETag: ETag(tools), CreatedAt: time.Now().Add(-time.Minute)}
+	assert.NoError(t, cache.Store(ctx, "analytics/prod", stored))
+	manifest, fresh, err = cache.Load(ctx, "analytics/prod")
+	if !assert.NoError(t, err) || !assert.NotNil(t, manifest) {
+		return
+	}
+	assert.True(t, fresh)
+	assert.EqualValues(t, stored.ETag, manifest.ETag)
+	assert.EqualValues(t, "query", manifest.Tools[0].Name)
+
+	// same ETag while fresh – entry kept as is
+	assert.NoError(t, cache.Store(ctx, "analytics/prod", &Manifest{Tools: tools, ETag: ETag(tools), CreatedAt: time.Now()}))
+	manifest, _, _ = cache.Load(ctx, "analytics/prod")
+	assert.True(t, manifest.CreatedAt.Equal(stored.CreatedAt))
+
+	// changed tool set – entry replaced
+	changed := []mcpschema.Tool{{Name: "query"}, {Name: "export"}}
+	assert.NotEqual(t, ETag(tools), ETag(changed))
+	assert.NoError(t, cache.Store(ctx, "analytics/prod", &Manifest{Tools: changed, ETag: ETag(changed), CreatedAt: time.Now()}))
+	manifest, _, _ = cache.Load(ctx, "analytics/prod")
+	assert.Len(t, manifest.Tools, 2)
+
+	// expired entry is still returned, but not fresh
+	expired := NewManifestCache(cache.URL, time.Nanosecond)
+	manifest, fresh, err = expired.Load(ctx, "analytics/prod")
+	assert.NoError(t, err)
+	assert.NotNil(t, manifest)
+	assert.False(t, fresh)
+}
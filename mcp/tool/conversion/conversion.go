@@ -4,9 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
-	"sort"
-	"strings"
-	"time"
 
 	"github.com/viant/fluxor-mcp/internal/syncmap"
 	"github.com/viant/fluxor/model/types"
@@ -38,7 +35,13 @@ func cacheKey(sig *types.Signature) string {
 }
 
 // BuildSchema converts a Fluxor action signature into an MCP Tool metadata
-// structure.  The result is cached so that subsequent conversions of the same
+// structure. Input and output fields are documented through struct tags –
+// description, choice, default, example, format, minimum, maximum, pattern,
+// required and the other keywords listed in applyTags – e.g.
+//
+//	Limit int `json:"limit,omitempty" description:"page size" minimum:"1" maximum:"100" default:"20"`
+//
+// The result is cached so that subsequent conversions of the same
 // signature return instantly.
 func BuildSchema(sig *types.Signature) (schema.Tool, error) {
 	if sig == nil {
@@ -74,6 +77,7 @@ func buildSchemaUncached(sig *types.Signature) (schema.Tool, error) {
 	if err := inputSchema.Load(sample); err != nil {
 		return schema.Tool{}, fmt.Errorf("failed to build input schema for %s: %w", sig.Name, err)
 	}
+	inputSchema.Required = applyTags(inputSchema.Properties, inputSchema.Required, sig.Input)
 	var props map[string]map[string]interface{}
 	var required []string
 	if sig.Output.Kind() == reflect.Pointer {
@@ -81,6 +85,7 @@ func buildSchemaUncached(sig *types.Signature) (schema.Tool, error) {
 	} else {
 		props, required = schema.StructToProperties(sig.Output)
 	}
+	required = applyTags(props, required, sig.Output)
 	outputSchema := &schema.ToolOutputSchema{Properties: props, Required: required, Type: "object"}
 	desc := sig.Description
 	return schema.Tool{Name: sig.Name, Description: &desc, InputSchema: inputSchema, OutputSchema: outputSchema}, nil
@@ -117,7 +122,7 @@ func TypeFromInputSchema(inputSchema schema.ToolInputSchema) (reflect.Type, erro
 		return reflect.StructOf([]reflect.StructField{}), nil
 	}
 
-	fields, err := buildFields(inputSchema.Properties, inputSchema.Required)
+	fields, err := newResolver(objectDocument(inputSchema.Properties, inputSchema.Required)).fields(inputSchema.Properties, inputSchema.Required)
 	if err != nil {
 		return nil, err
 	}
@@ -137,7 +142,7 @@ func TypeFromOutputSchema(outputSchema schema.ToolOutputSchema) (reflect.Type, e
 		return reflect.StructOf([]reflect.StructField{}), nil
 	}
 
-	fields, err := buildFields(outputSchema.Properties, outputSchema.Required)
+	fields, err := newResolver(objectDocument(outputSchema.Properties, outputSchema.Required)).fields(outputSchema.Properties, outputSchema.Required)
 	if err != nil {
 		return nil, err
 	}
@@ -147,143 +152,19 @@ func TypeFromOutputSchema(outputSchema schema.ToolOutputSchema) (reflect.Type, e
 	return t, nil
 }
 
-func buildFields(props map[string]map[string]interface{}, required []string) ([]reflect.StructField, error) {
-	keys := make([]string, 0, len(props))
-	for name := range props {
-		keys = append(keys, name)
-	}
-	sort.Strings(keys)
-	var fields []reflect.StructField
-	requiredSet := make(map[string]struct{}, len(required))
-	for _, n := range required {
-		requiredSet[n] = struct{}{}
-	}
-	for _, name := range keys {
-		def := props[name]
-		fieldType, err := goTypeFromDef(def)
-		if err != nil {
-			return nil, fmt.Errorf("failed to determine type for field %q: %w", name, err)
-		}
-		tagName := name
-		if _, ok := requiredSet[name]; !ok {
-			tagName += ",omitempty"
-		}
-
-		// ------------------------------------------------------------------
-		// Build struct tag with additional metadata (description, enum).
-		// Example: `json:"foo,omitempty" description:"A foo field" choice:"o1" choice:"o2"`
-		// ------------------------------------------------------------------
-
-		tagParts := []string{fmt.Sprintf("json:%q", tagName)}
-
-		if desc, ok := def["description"].(string); ok && desc != "" {
-			tagParts = append(tagParts, fmt.Sprintf("description:%q", desc))
-		}
-
-		if rawEnum, ok := def["enum"].([]interface{}); ok {
-			for _, ev := range rawEnum {
-				switch v := ev.(type) {
-				case string:
-					tagParts = append(tagParts, fmt.Sprintf("choice:%q", v))
-				case fmt.Stringer:
-					tagParts = append(tagParts, fmt.Sprintf("choice:%q", v.String()))
-				default:
-					// ignore non-string enum values for tag purposes
-				}
-			}
-		}
-
-		tag := reflect.StructTag(strings.Join(tagParts, " "))
-
-		fields = append(fields, reflect.StructField{
-			Name: strings.Title(name),
-			Type: fieldType,
-			Tag:  tag,
-		})
-	}
-	return fields, nil
-}
-
-func goTypeFromDef(def map[string]interface{}) (reflect.Type, error) {
-	rawType, _ := def["type"]
-	var typeStr string
-	switch v := rawType.(type) {
-	case string:
-		typeStr = v
-	case []interface{}:
-		if len(v) > 0 {
-			if s, ok := v[0].(string); ok {
-				typeStr = s
-			}
-		}
-	}
-	switch typeStr {
-	case "string":
-		if format, ok := def["format"].(string); ok && (format == "date-time" || format == "date") {
-			return reflect.TypeOf(time.Time{}), nil
-		}
-		return reflect.TypeOf(""), nil
-	case "integer":
-		return reflect.TypeOf(int64(0)), nil
-	case "number":
-		return reflect.TypeOf(float64(0)), nil
-	case "boolean":
-		return reflect.TypeOf(true), nil
-	case "object":
-		nested := map[string]map[string]interface{}{}
-		var nestedRequired []string
-		if rawReq, ok := def["required"].([]interface{}); ok {
-			for _, raw := range rawReq {
-				if s, ok := raw.(string); ok {
-					nestedRequired = append(nestedRequired, s)
-				}
-			}
-		}
-		if raw, ok := def["properties"].(map[string]interface{}); ok {
-			for k, v := range raw {
-				if m, ok := v.(map[string]interface{}); ok {
-					nested[k] = m
-				}
-			}
-		}
-		fields, err := buildFields(nested, nestedRequired)
-		if err != nil {
-			return nil, err
-		}
-		nestedType := reflect.StructOf(fields)
-		RegisterType(nestedType)
-		return nestedType, nil
-	case "array":
-		if raw, ok := def["items"].(map[string]interface{}); ok {
-			itemType, err := goTypeFromDef(raw)
-			if err != nil {
-				return nil, err
-			}
-			return reflect.SliceOf(itemType), nil
-		}
-		return reflect.SliceOf(reflect.TypeOf(new(interface{})).Elem()), nil
-	default:
-		return reflect.TypeOf(new(interface{})).Elem(), nil
-	}
-}
-
-// ToStruct builds a Go struct type from JSON Schema and unmarshals the raw payload.
 // ToStruct builds a Go struct type from JSON Schema and unmarshals the raw payload.
 // It returns the populated Go value as interface{} (a pointer to the generated struct).
 // Returning the concrete value instead of reflect.Value makes the helper easier to use
 // by callers that do not need to work with reflection directly.
 func ToStruct(schemaJSON, payloadJSON []byte) (any, error) {
-	var schemaInput schema.ToolInputSchema
-	if err := json.Unmarshal(schemaJSON, &schemaInput); err != nil {
+	var document map[string]interface{}
+	if err := json.Unmarshal(schemaJSON, &document); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal schema: %w", err)
 	}
-	// build Go struct fields from JSON schema properties
-	fields, err := buildFields(schemaInput.Properties, schemaInput.Required)
+	structType, err := TypeFromSchema(document)
 	if err != nil {
 		return nil, err
 	}
-	structType := reflect.StructOf(fields)
-	RegisterType(structType)
 	instPtr := reflect.New(structType)
 	if err := json.Unmarshal(payloadJSON, instPtr.Interface()); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/viant/fluxor/model/types"
 )
 
 func TestToStructAndToJSON_RoundTrip(t *testing.T) {
@@ -81,6 +82,18 @@ func TestToStructAndToJSON_RoundTrip(t *testing.T) {
            }`,
 			payloadJSON: `{"meta":{"created":"2023-06-01T12:00:00Z","tags":["go","test"]},"count":42}`,
 		},
+		{
+			name: "referenced definitions",
+			schemaJSON: `{
+               "$defs": { "tag": { "type": "object", "properties": { "key": { "type": "string" } }, "required": ["key"] } },
+               "properties": {
+                   "tags": { "type": "array", "items": { "$ref": "#/$defs/tag" } },
+                   "labels": { "type": "object", "additionalProperties": { "type": "string" } }
+               },
+               "type": "object"
+           }`,
+			payloadJSON: `{"tags":[{"key":"env"}],"labels":{"team":"ads"}}`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -152,3 +165,43 @@ func TestDescriptionAndEnumTags(t *testing.T) {
 	assert.Contains(t, tag, `choice:"open"`)
 	assert.Contains(t, tag, `choice:"closed"`)
 }
+
+// TestBuildSchema_Tags verifies that documented struct tags are advertised
+// in the generated input schema. Non-pointer fields without omitempty, tagged
+// or not, stay required unless tagged required:"false"; required tags that do
+// not parse as a boolean are ignored.
+func TestBuildSchema_Tags(t *testing.T) {
+	type Filter struct {
+		Field  string  `json:"field" description:"column name" example:"country"`
+		Value  float64 `json:"value,omitempty" required:"1"`
+		Negate bool    `json:"negate" required:"F"`
+		Scope  string  `json:"scope,omitempty" required:"yes"`
+	}
+	type Input struct {
+		Query   string   `json:"query" description:"search text" pattern:"^\\w+$" example:"shoes" example:"boots"`
+		Page    int      `json:"page"`
+		Limit   int      `json:"limit,omitempty" minimum:"1" maximum:"100" default:"20"`
+		Level   int      `json:"level,omitempty" choice:"1" choice:"2"`
+		Mode    string   `json:"mode" choice:"fast" choice:"safe" default:"safe" required:"false"`
+		Email   string   `json:"email,omitempty" format:"email"`
+		Filters []Filter `json:"filters,omitempty"`
+	}
+	tool, err := BuildSchema(&types.Signature{Name: "tags/search", Input: reflect.TypeOf(Input{}), Output: reflect.TypeOf(struct{}{})})
+	require.NoError(t, err)
+
+	expect := `{"type":"object","required":["query","page"],"properties":{
+		"query":{"type":"string","description":"search text","pattern":"^\\w+$","examples":["shoes","boots"]},
+		"page":{"type":"integer"},
+		"limit":{"type":"integer","minimum":1,"maximum":100,"default":20},
+		"level":{"type":"integer","enum":[1,2]},
+		"mode":{"type":"string","enum":["fast","safe"],"default":"safe"},
+		"email":{"type":"string","format":"email"},
+		"filters":{"type":"array","items":{"type":"object","required":["field","value"],"properties":{
+			"field":{"type":"string","description":"column name","examples":["country"]},
+			"value":{"type":"number"},
+			"negate":{"type":"boolean"},
+			"scope":{"type":"string"}}}}}}`
+	actual, err := json.Marshal(tool.InputSchema)
+	require.NoError(t, err)
+	assert.JSONEq(t, expect, string(actual))
+}
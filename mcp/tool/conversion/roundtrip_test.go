@@ -0,0 +1,93 @@
+package conversion
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/viant/fluxor/model/types"
+	schema "github.com/viant/mcp-protocol/schema"
+)
+
+// TestSchemaRoundTrip verifies that a tool re-served from the type generated
+// by TypeFromSchema advertises the schema it was generated from, with
+// references and allOf resolved and oneOf/anyOf merged into a tagged union.
+func TestSchemaRoundTrip(t *testing.T) {
+	testCases := []struct {
+		description string
+		schemaJSON  string
+		expectJSON  string // resolved schema when composition keywords are used
+	}{
+		{
+			description: "numeric constraints and default",
+			schemaJSON:  `{"type":"object","properties":{"limit":{"type":"integer","description":"page size","minimum":1,"maximum":100,"default":10},"ratio":{"type":"number","exclusiveMinimum":0,"multipleOf":0.5}}}`,
+		},
+		{
+			description: "string constraints and format",
+			schemaJSON:  `{"type":"object","properties":{"name":{"type":"string","pattern":"^[a-z]+$","minLength":1,"maxLength":32},"email":{"type":"string","format":"email"},"since":{"type":"string","format":"date"}},"required":["name"]}`,
+		},
+		{
+			description: "enums",
+			schemaJSON:  `{"type":"object","properties":{"level":{"type":"integer","enum":[1,2,3]},"ratio":{"type":"number","enum":[0.5,1]},"mode":{"type":"string","enum":["fast","safe"],"default":"safe"},"strict":{"type":"boolean","default":true}}}`,
+		},
+		{
+			description: "nested required and constraints",
+			schemaJSON: `{"type":"object","properties":{
+				"filter":{"type":"object","properties":{"field":{"type":"string"},"op":{"type":"string","enum":["eq","ne"]},"value":{"type":"number","minimum":0}},"required":["field","op"]},
+				"rows":{"type":"array","maxItems":5,"items":{"type":"object","properties":{"id":{"type":"integer","minimum":1}},"required":["id"]}}},
+				"required":["filter"]}`,
+		},
+		{
+			description: "map values",
+			schemaJSON: `{"type":"object","properties":{
+				"labels":{"type":"object","additionalProperties":{"type":"string"}},
+				"limits":{"type":"object","additionalProperties":{"type":"object","properties":{"max":{"type":"integer","minimum":1,"description":"upper bound"},"unit":{"type":"string","enum":["s","ms"]}},"required":["max"]}},
+				"groups":{"type":"array","items":{"type":"object","additionalProperties":{"type":"array","items":{"type":"object","properties":{"email":{"type":"string","format":"email"}},"required":["email"]}}}}}}`,
+		},
+		{
+			description: "$ref",
+			schemaJSON: `{"type":"object","$defs":{"range":{"type":"object","properties":{"from":{"type":"integer","minimum":0},"to":{"type":"integer","maximum":100}},"required":["from"]}},
+				"properties":{"range":{"$ref":"#/$defs/range"},"ranges":{"type":"object","additionalProperties":{"$ref":"#/$defs/range"}}},"required":["range"]}`,
+			expectJSON: `{"type":"object","properties":{
+				"range":{"type":"object","properties":{"from":{"type":"integer","minimum":0},"to":{"type":"integer","maximum":100}},"required":["from"]},
+				"ranges":{"type":"object","additionalProperties":{"type":"object","properties":{"from":{"type":"integer","minimum":0},"to":{"type":"integer","maximum":100}},"required":["from"]}}},"required":["range"]}`,
+		},
+		{
+			description: "allOf",
+			schemaJSON:  `{"type":"object","properties":{"page":{"allOf":[{"type":"object","properties":{"size":{"type":"integer","minimum":1,"default":10}}},{"properties":{"token":{"type":"string","pattern":"^[a-f0-9]+$"}},"required":["token"]}]}}}`,
+			expectJSON:  `{"type":"object","properties":{"page":{"type":"object","properties":{"size":{"type":"integer","minimum":1,"default":10},"token":{"type":"string","pattern":"^[a-f0-9]+$"}},"required":["token"]}}}`,
+		},
+		{
+			description: "anyOf",
+			schemaJSON: `{"type":"object","properties":{
+				"target":{"anyOf":[{"type":"object","properties":{"id":{"type":"integer","minimum":1}},"required":["id"]},{"type":"object","properties":{"email":{"type":"string","format":"email"}},"required":["email"]}]},
+				"targets":{"type":"object","additionalProperties":{"oneOf":[{"type":"object","properties":{"id":{"type":"integer","minimum":1}}},{"type":"object","properties":{"name":{"type":"string","maxLength":64}}}]}}}}`,
+			expectJSON: `{"type":"object","properties":{
+				"target":{"type":"object","properties":{"id":{"type":"integer","minimum":1},"email":{"type":"string","format":"email"}}},
+				"targets":{"type":"object","additionalProperties":{"type":"object","properties":{"id":{"type":"integer","minimum":1},"name":{"type":"string","maxLength":64}}}}}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		var document map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(testCase.schemaJSON), &document), testCase.description)
+		inputType, err := TypeFromSchema(document)
+		require.NoError(t, err, testCase.description)
+		tool, err := BuildSchema(&types.Signature{Name: "roundtrip/" + testCase.description, Input: inputType, Output: reflect.TypeOf(struct{}{})})
+		require.NoError(t, err, testCase.description)
+
+		expectJSON := testCase.expectJSON
+		if expectJSON == "" {
+			expectJSON = testCase.schemaJSON
+		}
+		var original schema.ToolInputSchema
+		require.NoError(t, json.Unmarshal([]byte(expectJSON), &original), testCase.description)
+		expect, err := json.Marshal(original)
+		require.NoError(t, err)
+		actual, err := json.Marshal(tool.InputSchema)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(expect), string(actual), testCase.description)
+	}
+}
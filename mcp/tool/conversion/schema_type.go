@@ -0,0 +1,515 @@
+package conversion
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	schema "github.com/viant/mcp-protocol/schema"
+)
+
+var (
+	interfaceType = reflect.TypeOf(new(interface{})).Elem()
+	stringType    = reflect.TypeOf("")
+	timeType      = reflect.TypeOf(time.Time{})
+)
+
+// TypeFromSchema converts a complete JSON Schema document of an object into a
+// dynamically generated Go struct type. Unlike TypeFromInputSchema it keeps
+// the document root, so "$ref" pointers into "$defs" or "definitions" of the
+// root resolve as well.
+//
+// Composition keywords are mapped as follows: "$ref" is replaced by the
+// referenced schema (recursive references become interface{}), allOf is
+// merged into a single schema, oneOf/anyOf become a tagged union – a struct
+// with the optional fields of all object alternatives – or, for alternatives
+// of different kinds, interface{} holding the raw JSON value, and an object
+// with additionalProperties but no properties becomes a map. Optional scalar
+// properties with a default are pointers, so that an explicit zero value is
+// told apart from an omitted one.
+func TypeFromSchema(document map[string]interface{}) (reflect.Type, error) {
+	r := newResolver(document)
+	def, release := r.normalize(document)
+	defer release()
+	properties := propertyMap(def["properties"])
+	if len(properties) == 0 {
+		return reflect.StructOf([]reflect.StructField{}), nil
+	}
+	fields, err := r.fields(properties, stringSlice(def["required"]))
+	if err != nil {
+		return nil, err
+	}
+	t := reflect.StructOf(fields)
+	RegisterType(t)
+	return t, nil
+}
+
+// objectDocument returns a schema document of an object with properties.
+func objectDocument(properties map[string]map[string]interface{}, required []string) map[string]interface{} {
+	return map[string]interface{}{"type": "object", "properties": properties, "required": required}
+}
+
+// resolver converts schemas of one document, resolving "$ref" pointers
+// against the document root or "$defs" nested in the document.
+type resolver struct {
+	root      map[string]interface{}
+	scoped    map[string]map[string]interface{}
+	resolving map[string]bool
+}
+
+func newResolver(root map[string]interface{}) *resolver {
+	return &resolver{root: root, scoped: map[string]map[string]interface{}{}, resolving: map[string]bool{}}
+}
+
+func (r *resolver) fields(props map[string]map[string]interface{}, required []string) ([]reflect.StructField, error) {
+	keys := make([]string, 0, len(props))
+	for name := range props {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	var fields []reflect.StructField
+	requiredSet := make(map[string]struct{}, len(required))
+	for _, n := range required {
+		requiredSet[n] = struct{}{}
+	}
+	for _, name := range keys {
+		fieldType, def, err := r.goType(props[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine type for field %q: %w", name, err)
+		}
+		tagName := name
+		pointer := false
+		if _, ok := requiredSet[name]; !ok {
+			tagName += ",omitempty"
+			if _, hasDefault := def["default"]; hasDefault && isScalar(fieldType) {
+				// an explicit zero value must not be dropped in favour of the default
+				fieldType, pointer = reflect.PointerTo(fieldType), true
+			}
+		}
+
+		// ------------------------------------------------------------------
+		// Build struct tag with additional metadata (description, keywords,
+		// default, enum) so that BuildSchema can advertise them again.
+		// Example: `json:"foo,omitempty" description:"A foo field" minimum:"1" choice:"o1" choice:"o2"`
+		// ------------------------------------------------------------------
+
+		tagParts := []string{fmt.Sprintf("json:%q", tagName)}
+
+		if desc, ok := def["description"].(string); ok && desc != "" {
+			tagParts = append(tagParts, fmt.Sprintf("description:%q", desc))
+		}
+		tagParts = append(tagParts, keywordTags(def)...)
+		if pointer {
+			tagParts = append(tagParts, `nullable:"false"`)
+		}
+
+		tag := reflect.StructTag(strings.Join(tagParts, " "))
+
+		fields = append(fields, reflect.StructField{
+			Name: strings.Title(name),
+			Type: fieldType,
+			Tag:  tag,
+		})
+	}
+	return fields, nil
+}
+
+// isScalar reports whether t is a string, number, boolean or time type.
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int64, reflect.Float64:
+		return true
+	}
+	return t == timeType
+}
+
+// goType returns the Go type of a schema together with the schema after
+// reference resolution and allOf merging.
+func (r *resolver) goType(def map[string]interface{}) (reflect.Type, map[string]interface{}, error) {
+	resolved, release := r.normalize(def)
+	defer release()
+	if resolved == nil { // recursive reference
+		return interfaceType, def, nil
+	}
+	def = resolved
+	if _, ok := def["properties"]; !ok {
+		if branches := schemaList(def["oneOf"], def["anyOf"]); len(branches) > 0 {
+			t, err := r.union(branches)
+			return t, def, err
+		}
+	}
+	switch schemaType(def) {
+	case "string":
+		if format, ok := def["format"].(string); ok && (format == "date-time" || format == "date") {
+			return timeType, def, nil
+		}
+		return stringType, def, nil
+	case "integer":
+		return reflect.TypeOf(int64(0)), def, nil
+	case "number":
+		return reflect.TypeOf(float64(0)), def, nil
+	case "boolean":
+		return reflect.TypeOf(true), def, nil
+	case "object":
+		properties := propertyMap(def["properties"])
+		if len(properties) == 0 {
+			switch additional := def["additionalProperties"].(type) {
+			case map[string]interface{}:
+				valueType, _, err := r.goType(additional)
+				if err != nil {
+					return nil, nil, err
+				}
+				return reflect.MapOf(stringType, valueType), def, nil
+			case bool:
+				if !additional {
+					return reflect.StructOf([]reflect.StructField{}), def, nil
+				}
+			}
+			return reflect.MapOf(stringType, interfaceType), def, nil
+		}
+		fields, err := r.fields(properties, stringSlice(def["required"]))
+		if err != nil {
+			return nil, nil, err
+		}
+		nestedType := reflect.StructOf(fields)
+		RegisterType(nestedType)
+		return nestedType, def, nil
+	case "array":
+		if raw, ok := def["items"].(map[string]interface{}); ok {
+			itemType, _, err := r.goType(raw)
+			if err != nil {
+				return nil, nil, err
+			}
+			return reflect.SliceOf(itemType), def, nil
+		}
+		return reflect.SliceOf(interfaceType), def, nil
+	default:
+		return interfaceType, def, nil
+	}
+}
+
+// union maps oneOf/anyOf alternatives to a Go type. A single non-null
+// alternative keeps its own type, object alternatives are merged into a
+// tagged union whose fields are all optional and alternatives sharing a Go
+// type use it; anything else is held as raw JSON in interface{}.
+func (r *resolver) union(branches []map[string]interface{}) (reflect.Type, error) {
+	var alternatives []map[string]interface{}
+	var releases []func()
+	defer func() {
+		for _, release := range releases {
+			release()
+		}
+	}()
+	for _, branch := range branches {
+		resolved, release := r.normalize(branch)
+		releases = append(releases, release)
+		if resolved == nil {
+			return interfaceType, nil
+		}
+		if types := schemaTypes(resolved["type"]); len(types) == 1 && types[0] == "null" {
+			continue
+		}
+		alternatives = append(alternatives, resolved)
+	}
+	switch len(alternatives) {
+	case 0:
+		return interfaceType, nil
+	case 1:
+		t, _, err := r.goType(alternatives[0])
+		return t, err
+	}
+	objects := true
+	for _, alternative := range alternatives {
+		if schemaType(alternative) != "object" || len(propertyMap(alternative["properties"])) == 0 {
+			objects = false
+			break
+		}
+	}
+	if objects {
+		t, _, err := r.goType(map[string]interface{}{"type": "object", "properties": unionProperties(alternatives)})
+		return t, err
+	}
+	var common reflect.Type
+	for _, alternative := range alternatives {
+		t, _, err := r.goType(alternative)
+		if err != nil {
+			return nil, err
+		}
+		if common != nil && t != common {
+			return interfaceType, nil
+		}
+		common = t
+	}
+	return common, nil
+}
+
+// normalize returns def with "$ref" replaced by the referenced schema –
+// sibling keywords take precedence – and allOf merged in. It returns nil for
+// a reference already being resolved, i.e. a recursive schema; release ends
+// the scope of the references resolved.
+func (r *resolver) normalize(def map[string]interface{}) (map[string]interface{}, func()) {
+	def, release := r.dereference(def)
+	if def == nil {
+		return nil, release
+	}
+	if branches := schemaList(def["allOf"]); len(branches) > 0 {
+		merged := without(def, "allOf")
+		for _, branch := range branches {
+			resolved, releaseBranch := r.normalize(branch)
+			if resolved != nil {
+				mergeSchema(merged, resolved)
+			}
+			releaseBranch()
+		}
+		def = merged
+	}
+	return def, release
+}
+
+// dereference returns def with "$ref" replaced by the referenced schema, as
+// normalize does, leaving allOf as is.
+func (r *resolver) dereference(def map[string]interface{}) (map[string]interface{}, func()) {
+	var refs []string
+	release := func() {
+		for _, ref := range refs {
+			delete(r.resolving, ref)
+		}
+	}
+	r.scope(def)
+	for {
+		ref, ok := def["$ref"].(string)
+		if !ok {
+			break
+		}
+		if r.resolving[ref] {
+			return nil, release
+		}
+		target := r.lookup(ref)
+		if target == nil { // external or dangling reference
+			def = without(def, "$ref")
+			break
+		}
+		r.resolving[ref] = true
+		refs = append(refs, ref)
+		r.scope(target)
+		merged := without(target)
+		for k, v := range def {
+			if k != "$ref" {
+				merged[k] = v
+			}
+		}
+		def = merged
+	}
+	return def, release
+}
+
+// lookup resolves a local reference: a JSON pointer into the document root
+// or, failing that, a "$defs"/"definitions" entry of a nested schema.
+func (r *resolver) lookup(ref string) map[string]interface{} {
+	if !strings.HasPrefix(ref, "#") {
+		return nil
+	}
+	if target, ok := pointer(r.root, strings.TrimPrefix(ref, "#")).(map[string]interface{}); ok {
+		return target
+	}
+	return r.scoped[ref]
+}
+
+// scope records definitions nested in def so that they can be referenced.
+func (r *resolver) scope(def map[string]interface{}) {
+	for _, key := range []string{"$defs", "definitions"} {
+		defs, ok := def[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for name, raw := range defs {
+			ref := "#/" + key + "/" + name
+			if target, ok := raw.(map[string]interface{}); ok && r.scoped[ref] == nil {
+				r.scoped[ref] = target
+			}
+		}
+	}
+}
+
+// pointer evaluates a JSON pointer (RFC 6901) against node.
+func pointer(node interface{}, path string) interface{} {
+	if path == "" {
+		return node
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil
+	}
+	for _, token := range strings.Split(path[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch actual := node.(type) {
+		case map[string]interface{}:
+			node = actual[token]
+		case map[string]map[string]interface{}:
+			if value, ok := actual[token]; ok {
+				node = value
+			} else {
+				node = nil
+			}
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(actual) {
+				return nil
+			}
+			node = actual[index]
+		default:
+			return nil
+		}
+	}
+	return node
+}
+
+// mergeSchema merges an allOf branch into dst: properties and required are
+// combined, other keywords are taken from src unless dst defines them.
+func mergeSchema(dst, src map[string]interface{}) {
+	properties := map[string]map[string]interface{}{}
+	for name, def := range propertyMap(dst["properties"]) {
+		properties[name] = def
+	}
+	for name, def := range propertyMap(src["properties"]) {
+		if _, ok := properties[name]; !ok {
+			properties[name] = def
+		}
+	}
+	required := stringSlice(dst["required"])
+	for _, name := range stringSlice(src["required"]) {
+		if !contains(required, name) {
+			required = append(required, name)
+		}
+	}
+	for k, v := range src {
+		if _, ok := dst[k]; !ok {
+			dst[k] = v
+		}
+	}
+	if len(properties) > 0 {
+		dst["properties"] = properties
+	}
+	if len(required) > 0 {
+		dst["required"] = required
+	}
+}
+
+// unionProperties combines the properties of object alternatives; the enum
+// or const values of a property defined by several alternatives – typically
+// the discriminator – are combined into one enum.
+func unionProperties(alternatives []map[string]interface{}) map[string]map[string]interface{} {
+	properties := map[string]map[string]interface{}{}
+	for _, alternative := range alternatives {
+		for name, def := range propertyMap(alternative["properties"]) {
+			existing, ok := properties[name]
+			if !ok {
+				properties[name] = def
+				continue
+			}
+			existingValues, values := enumValues(existing), enumValues(def)
+			if len(existingValues) == 0 || len(values) == 0 {
+				continue
+			}
+			combined := without(existing, "const")
+			enum := append([]interface{}{}, existingValues...)
+			for _, value := range values {
+				if !inEnum(enum, value) {
+					enum = append(enum, value)
+				}
+			}
+			combined["enum"] = enum
+			properties[name] = combined
+		}
+	}
+	return properties
+}
+
+// schemaType returns the single non-null type of def, inferring "object" and
+// "array" from properties and items; it returns "" for mixed types.
+func schemaType(def map[string]interface{}) string {
+	var types []string
+	for _, candidate := range schemaTypes(def["type"]) {
+		if candidate != "null" {
+			types = append(types, candidate)
+		}
+	}
+	switch {
+	case len(types) == 1:
+		return types[0]
+	case len(types) > 1:
+		return ""
+	case def["properties"] != nil || def["additionalProperties"] != nil:
+		return "object"
+	case def["items"] != nil:
+		return "array"
+	}
+	return ""
+}
+
+// enumValues returns the enum values of def, or its const value.
+func enumValues(def map[string]interface{}) []interface{} {
+	if enum, ok := def["enum"].([]interface{}); ok {
+		return enum
+	}
+	if value, ok := def["const"]; ok {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// propertyMap normalises the "properties" keyword.
+func propertyMap(raw interface{}) map[string]map[string]interface{} {
+	switch actual := raw.(type) {
+	case map[string]map[string]interface{}:
+		return actual
+	case schema.ToolInputSchemaProperties:
+		return actual
+	case map[string]interface{}:
+		result := make(map[string]map[string]interface{}, len(actual))
+		for k, v := range actual {
+			if m, ok := v.(map[string]interface{}); ok {
+				result[k] = m
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// schemaList collects the schemas of composition keywords.
+func schemaList(raws ...interface{}) []map[string]interface{} {
+	var result []map[string]interface{}
+	for _, raw := range raws {
+		items, _ := raw.([]interface{})
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok {
+				result = append(result, m)
+			}
+		}
+	}
+	return result
+}
+
+// without returns a shallow copy of def without the keys.
+func without(def map[string]interface{}, keys ...string) map[string]interface{} {
+	result := make(map[string]interface{}, len(def))
+	for k, v := range def {
+		result[k] = v
+	}
+	for _, key := range keys {
+		delete(result, key)
+	}
+	return result
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -1,12 +1,14 @@
 package conversion
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
 	schema "github.com/viant/mcp-protocol/schema"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTypeFromInputSchema(t *testing.T) {
@@ -55,3 +57,69 @@ func TestTypeFromInputSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestTypeFromSchema(t *testing.T) {
+	testCases := []struct {
+		description string
+		schemaJSON  string
+		expect      map[string]string
+	}{
+		{
+			description: "$ref into $defs",
+			schemaJSON: `{"type":"object","$defs":{"user":{"type":"object","properties":{"id":{"type":"integer"}},"required":["id"]}},
+				"properties":{"owner":{"$ref":"#/$defs/user","description":"owner"},"members":{"type":"array","items":{"$ref":"#/$defs/user"}}}}`,
+			expect: map[string]string{"Owner": `struct { Id int64 "json:\"id\"" }`, "Members": `[]struct { Id int64 "json:\"id\"" }`},
+		},
+		{
+			description: "definitions nested in a property",
+			schemaJSON:  `{"type":"object","properties":{"filter":{"definitions":{"range":{"type":"number"}},"type":"object","properties":{"min":{"$ref":"#/definitions/range"}}}}}`,
+			expect:      map[string]string{"Filter": `struct { Min float64 "json:\"min,omitempty\"" }`},
+		},
+		{
+			description: "allOf merge",
+			schemaJSON: `{"type":"object","$defs":{"named":{"type":"object","properties":{"name":{"type":"string"}},"required":["name"]}},
+				"properties":{"item":{"allOf":[{"$ref":"#/$defs/named"},{"properties":{"size":{"type":"integer"}}}]}}}`,
+			expect: map[string]string{"Item": `struct { Name string "json:\"name\""; Size int64 "json:\"size,omitempty\"" }`},
+		},
+		{
+			description: "oneOf objects as tagged union",
+			schemaJSON: `{"type":"object","properties":{"shape":{"oneOf":[
+				{"type":"object","properties":{"kind":{"const":"circle"},"radius":{"type":"number"}},"required":["kind","radius"]},
+				{"type":"object","properties":{"kind":{"const":"square"},"side":{"type":"number"}},"required":["kind","side"]}]}}}`,
+			expect: map[string]string{"Shape": `struct { Kind interface {} "json:\"kind,omitempty\" choice:\"circle\" choice:\"square\""; Radius float64 "json:\"radius,omitempty\""; Side float64 "json:\"side,omitempty\"" }`},
+		},
+		{
+			description: "nullable anyOf and type list",
+			schemaJSON:  `{"type":"object","properties":{"note":{"anyOf":[{"type":"string"},{"type":"null"}]},"count":{"type":["null","integer"]}}}`,
+			expect:      map[string]string{"Note": "string", "Count": "int64"},
+		},
+		{
+			description: "mixed anyOf as raw JSON",
+			schemaJSON:  `{"type":"object","properties":{"value":{"anyOf":[{"type":"string"},{"type":"array","items":{"type":"string"}}]}}}`,
+			expect:      map[string]string{"Value": "interface {}"},
+		},
+		{
+			description: "additionalProperties as map",
+			schemaJSON:  `{"type":"object","properties":{"labels":{"type":"object","additionalProperties":{"type":"string"}},"extra":{"type":"object"},"closed":{"type":"object","additionalProperties":false}}}`,
+			expect:      map[string]string{"Labels": "map[string]string", "Extra": "map[string]interface {}", "Closed": "struct {}"},
+		},
+		{
+			description: "recursive $ref",
+			schemaJSON:  `{"type":"object","$defs":{"node":{"type":"object","properties":{"children":{"type":"array","items":{"$ref":"#/$defs/node"}}}}},"properties":{"root":{"$ref":"#/$defs/node"}}}`,
+			expect:      map[string]string{"Root": `struct { Children []interface {} "json:\"children,omitempty\"" }`},
+		},
+	}
+
+	for _, testCase := range testCases {
+		var document map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(testCase.schemaJSON), &document), testCase.description)
+		rType, err := TypeFromSchema(document)
+		require.NoError(t, err, testCase.description)
+		for fieldName, expect := range testCase.expect {
+			field, ok := rType.FieldByName(fieldName)
+			if assert.True(t, ok, "%v: expected field %s", testCase.description, fieldName) {
+				assert.Equal(t, expect, field.Type.String(), testCase.description)
+			}
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// numericKeywords are JSON Schema keywords with a numeric value carried
+// through struct tags of the same name.
+var numericKeywords = []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf", "minLength", "maxLength", "minItems", "maxItems"}
+
+// stringKeywords are JSON Schema keywords with a string value carried through
+// struct tags of the same name.
+var stringKeywords = []string{"format", "pattern"}
+
+// repeatedTagExpr matches every value of a tag key that may be repeated,
+// such as choice or example.
+var repeatedTagExpr = map[string]*regexp.Regexp{
+	"choice":  regexp.MustCompile(`(?:^|\s)choice:"((?:[^"\\]|\\.)*)"`),
+	"example": regexp.MustCompile(`(?:^|\s)example:"((?:[^"\\]|\\.)*)"`),
+}
+
+// keywordTags returns struct tag parts carrying the validation keywords and
+// default of a property schema, e.g. `minimum:"1" pattern:"^[a-z]+$"`.
+// Enum values are carried by choice tags: strings as is, other values as
+// JSON.
+func keywordTags(def map[string]interface{}) []string {
+	var parts []string
+	for _, keyword := range numericKeywords {
+		if value, ok := def[keyword].(float64); ok {
+			parts = append(parts, fmt.Sprintf("%s:%q", keyword, strconv.FormatFloat(value, 'g', -1, 64)))
+		}
+	}
+	for _, keyword := range stringKeywords {
+		if value, ok := def[keyword].(string); ok && value != "" {
+			parts = append(parts, fmt.Sprintf("%s:%q", keyword, value))
+		}
+	}
+	if value, ok := def["default"]; ok && value != nil {
+		parts = append(parts, fmt.Sprintf("default:%q", tagValue(value)))
+	}
+	for _, value := range enumValues(def) {
+		if value != nil {
+			parts = append(parts, fmt.Sprintf("choice:%q", tagValue(value)))
+		}
+	}
+	if examples, ok := def["examples"].([]interface{}); ok {
+		for _, value := range examples {
+			if value != nil {
+				parts = append(parts, fmt.Sprintf("example:%q", tagValue(value)))
+			}
+		}
+	}
+	return parts
+}
+
+// tagValue encodes a schema value for a struct tag: strings as is, other
+// values as JSON.
+func tagValue(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	if stringer, ok := value.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// applyTags adds the keywords carried by struct tags of t to properties
+// generated by schema.StructToProperties, recursing into nested structs, array
+// items and map values, and returns required adjusted by required tags. The supported tags are:
+//
+//	description:"text"          property description
+//	choice:"a" choice:"b"       enum values, decoded for the field type
+//	default:"10"                default value, decoded for the field type
+//	example:"x" example:"y"     examples
+//	format:"email"              string format
+//	minimum:"1" maximum:"100"   numeric bounds; also exclusiveMinimum,
+//	                            exclusiveMaximum and multipleOf
+//	minLength, maxLength        string length bounds
+//	minItems, maxItems          array length bounds
+//	pattern:"^[a-z]+$"          regular expression for strings
+//	required:"true|false"       marks a field required or optional regardless
+//	                            of omitempty and pointer types; any value
+//	                            strconv.ParseBool accepts, others are ignored
+//	nullable:"false"            a pointer field that does not accept null
+//
+// Values of non-string fields are read as JSON, e.g. `choice:"1"` on an int
+// field is the number 1.
+func applyTags(properties map[string]map[string]interface{}, required []string, t reflect.Type) []string {
+	if t = deref(t); t.Kind() != reflect.Struct {
+		return required
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		def, ok := properties[name]
+		if !ok || def == nil {
+			continue
+		}
+		applyFieldTags(def, field)
+		if flag, err := strconv.ParseBool(field.Tag.Get("required")); err == nil {
+			if !flag {
+				required = remove(required, name)
+			} else if !contains(required, name) {
+				required = append(required, name)
+			}
+		}
+		applyNestedTags(def, field.Type)
+	}
+	return required
+}
+
+// applyNestedTags applies the tags of the structs t is made of – directly,
+// as array items or as map values, including the tagged unions generated for
+// oneOf/anyOf – to def, the schema generated for t.
+func applyNestedTags(def map[string]interface{}, t reflect.Type) {
+	switch t = deref(t); t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if items, ok := def["items"].(map[string]interface{}); ok {
+			applyNestedTags(items, t.Elem())
+		}
+	case reflect.Map:
+		if values, ok := def["additionalProperties"].(map[string]interface{}); ok {
+			applyNestedTags(values, t.Elem())
+		}
+	case reflect.Struct:
+		if properties := propertyMap(def["properties"]); properties != nil {
+			required := applyTags(properties, stringSlice(def["required"]), t)
+			if len(required) > 0 {
+				def["required"] = required
+			} else {
+				delete(def, "required")
+			}
+		}
+	}
+}
+
+func applyFieldTags(def map[string]interface{}, field reflect.StructField) {
+	for _, keyword := range numericKeywords {
+		if raw, ok := field.Tag.Lookup(keyword); ok {
+			if value, err := strconv.ParseFloat(raw, 64); err == nil {
+				def[keyword] = value
+			}
+		}
+	}
+	for _, keyword := range stringKeywords {
+		if value := field.Tag.Get(keyword); value != "" {
+			def[keyword] = value
+		}
+	}
+	if raw, ok := field.Tag.Lookup("default"); ok {
+		def["default"] = tagSchemaValue(raw, field.Type)
+	}
+	if enum := repeatedTagValues(field, "choice"); len(enum) > 0 {
+		def["enum"] = enum
+	}
+	if examples := repeatedTagValues(field, "example"); len(examples) > 0 {
+		def["examples"] = examples
+	}
+	if field.Tag.Get("nullable") == "false" {
+		delete(def, "nullable")
+	}
+}
+
+// repeatedTagValues returns the decoded values of a repeatable tag key.
+func repeatedTagValues(field reflect.StructField, key string) []interface{} {
+	matches := repeatedTagExpr[key].FindAllStringSubmatch(string(field.Tag), -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	values := make([]interface{}, 0, len(matches))
+	for _, match := range matches {
+		raw := match[1]
+		if unquoted, err := strconv.Unquote(`"` + raw + `"`); err == nil {
+			raw = unquoted
+		}
+		values = append(values, tagSchemaValue(raw, field.Type))
+	}
+	return values
+}
+
+// tagSchemaValue decodes a struct tag value for a field of type t: the raw
+// text for string fields, JSON for others – falling back to the raw text.
+func tagSchemaValue(raw string, t reflect.Type) interface{} {
+	if deref(t).Kind() == reflect.String {
+		return raw
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return raw
+	}
+	return value
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func remove(values []string, value string) []string {
+	result := values[:0:0]
+	for _, candidate := range values {
+		if candidate != value {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
@@ -0,0 +1,310 @@
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	schema "github.com/viant/mcp-protocol/schema"
+)
+
+// FieldError describes a single schema violation at a dotted field path.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError aggregates all violations found in a value.
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, item := range e.Errors {
+		messages = append(messages, item.Field+": "+item.Message)
+	}
+	return "schema validation failed: " + strings.Join(messages, "; ")
+}
+
+// ValidateInput checks tool call arguments against the tool input schema
+// before execution. Arguments are compared in their JSON form, so Go values
+// supplied by library callers are accepted when they encode as expected. It
+// returns *ValidationError listing every violation or nil.
+func ValidateInput(inputSchema *schema.ToolInputSchema, args map[string]interface{}) error {
+	if inputSchema == nil {
+		return nil
+	}
+	return ValidateInputDocument(objectDocument(inputSchema.Properties, inputSchema.Required), args)
+}
+
+// ValidateInputDocument behaves like ValidateInput but takes a complete JSON
+// Schema document, as TypeFromSchema does, so that "$ref" pointers into a
+// root "$defs" resolve. Composition keywords are checked as well: a value
+// must be valid against every allOf schema, at least one anyOf schema and
+// exactly one oneOf schema.
+func ValidateInputDocument(document map[string]interface{}, args map[string]interface{}) error {
+	if document == nil {
+		return nil
+	}
+	value := args
+	if data, err := json.Marshal(args); err == nil {
+		value = nil
+		if err = json.Unmarshal(data, &value); err != nil {
+			return fmt.Errorf("failed to decode arguments: %w", err)
+		}
+	}
+	if value == nil {
+		value = map[string]interface{}{}
+	}
+	return validate(document, value)
+}
+
+// ValidateOutput checks a JSON-decoded value against the tool output schema.
+// It returns *ValidationError listing every violation or nil.
+func ValidateOutput(outputSchema *schema.ToolOutputSchema, value map[string]interface{}) error {
+	if outputSchema == nil {
+		return nil
+	}
+	return validate(objectDocument(outputSchema.Properties, outputSchema.Required), value)
+}
+
+func validate(document map[string]interface{}, value map[string]interface{}) error {
+	var errs []FieldError
+	v := &validator{resolver: newResolver(document)}
+	v.validateValue("", document, value, &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return &ValidationError{Errors: errs}
+}
+
+// validator checks values against the schemas of one document.
+type validator struct {
+	*resolver
+}
+
+func (v *validator) validateObject(path string, properties map[string]map[string]interface{}, required []string, value map[string]interface{}, errs *[]FieldError) {
+	for _, name := range required {
+		if item, ok := value[name]; !ok || item == nil {
+			*errs = append(*errs, FieldError{Field: fieldPath(path, name), Message: "is required"})
+		}
+	}
+	for name, def := range properties {
+		item, ok := value[name]
+		if !ok || item == nil {
+			continue
+		}
+		v.validateValue(fieldPath(path, name), def, item, errs)
+	}
+}
+
+func (v *validator) validateValue(path string, def map[string]interface{}, value interface{}, errs *[]FieldError) {
+	// references are released right away: a recursive schema is bounded by
+	// the value validated
+	def, release := v.dereference(def)
+	release()
+	if def == nil {
+		return
+	}
+	for _, branch := range schemaList(def["allOf"]) {
+		v.validateValue(path, branch, value, errs)
+	}
+	expected := schemaTypes(def["type"])
+	if len(expected) > 0 && !matchesAny(expected, value) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("expected %s, got %s", strings.Join(expected, " or "), jsonType(value))})
+		return
+	}
+	if enum, ok := def["enum"].([]interface{}); ok && len(enum) > 0 && !inEnum(enum, value) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must be one of %v", enum)})
+	}
+	if branches := schemaList(def["anyOf"]); len(branches) > 0 && v.matches(branches, value) == 0 {
+		*errs = append(*errs, FieldError{Field: path, Message: "must match at least one anyOf schema"})
+	}
+	if branches := schemaList(def["oneOf"]); len(branches) > 0 {
+		if matched := v.matches(branches, value); matched != 1 {
+			*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must match exactly one oneOf schema, matched %d", matched)})
+		}
+	}
+	switch actual := value.(type) {
+	case float64:
+		validateNumber(path, def, actual, errs)
+	case string:
+		validateString(path, def, actual, errs)
+	case map[string]interface{}:
+		nested := propertyMap(def["properties"])
+		v.validateObject(path, nested, stringSlice(def["required"]), actual, errs)
+		if additional, ok := def["additionalProperties"].(map[string]interface{}); ok && len(nested) == 0 {
+			for name, item := range actual {
+				if item != nil {
+					v.validateValue(fieldPath(path, name), additional, item, errs)
+				}
+			}
+		}
+	case []interface{}:
+		validateLength(path, def, "minItems", "maxItems", "items", len(actual), errs)
+		items, ok := def["items"].(map[string]interface{})
+		if !ok {
+			return
+		}
+		for i, item := range actual {
+			if item != nil {
+				v.validateValue(fmt.Sprintf("%s[%d]", path, i), items, item, errs)
+			}
+		}
+	}
+}
+
+// matches returns the number of branches value is valid against.
+func (v *validator) matches(branches []map[string]interface{}, value interface{}) int {
+	matched := 0
+	for _, branch := range branches {
+		var errs []FieldError
+		if v.validateValue("", branch, value, &errs); len(errs) == 0 {
+			matched++
+		}
+	}
+	return matched
+}
+
+func validateNumber(path string, def map[string]interface{}, value float64, errs *[]FieldError) {
+	if limit, ok := def["minimum"].(float64); ok && value < limit {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must be >= %v", limit)})
+	}
+	if limit, ok := def["maximum"].(float64); ok && value > limit {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must be <= %v", limit)})
+	}
+	if limit, ok := def["exclusiveMinimum"].(float64); ok && value <= limit {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must be > %v", limit)})
+	}
+	if limit, ok := def["exclusiveMaximum"].(float64); ok && value >= limit {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must be < %v", limit)})
+	}
+	if factor, ok := def["multipleOf"].(float64); ok && factor > 0 {
+		if quotient := value / factor; math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must be a multiple of %v", factor)})
+		}
+	}
+}
+
+func validateString(path string, def map[string]interface{}, value string, errs *[]FieldError) {
+	validateLength(path, def, "minLength", "maxLength", "characters", utf8.RuneCountInString(value), errs)
+	if pattern, ok := def["pattern"].(string); ok && pattern != "" {
+		if expr, err := regexp.Compile(pattern); err == nil && !expr.MatchString(value) {
+			*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must match pattern %s", pattern)})
+		}
+	}
+	format, _ := def["format"].(string)
+	var err error
+	switch format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, value)
+	case "date":
+		_, err = time.Parse(time.DateOnly, value)
+	}
+	if err != nil {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must be a %s, got %q", format, value)})
+	}
+}
+
+// validateLength checks the length of a string or array against the min and
+// max keywords; unit names what is counted.
+func validateLength(path string, def map[string]interface{}, minKeyword, maxKeyword, unit string, length int, errs *[]FieldError) {
+	if limit, ok := def[minKeyword].(float64); ok && float64(length) < limit {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must have at least %v %s", limit, unit)})
+	}
+	if limit, ok := def[maxKeyword].(float64); ok && float64(length) > limit {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must have at most %v %s", limit, unit)})
+	}
+}
+
+func fieldPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// schemaTypes normalises the "type" keyword which may be a string or a list.
+func schemaTypes(raw interface{}) []string {
+	switch actual := raw.(type) {
+	case string:
+		return []string{actual}
+	case []interface{}:
+		return stringSlice(actual)
+	case []string:
+		return actual
+	}
+	return nil
+}
+
+func stringSlice(raw interface{}) []string {
+	switch actual := raw.(type) {
+	case []string:
+		return actual
+	case []interface{}:
+		result := make([]string, 0, len(actual))
+		for _, item := range actual {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func matchesAny(expected []string, value interface{}) bool {
+	actual := jsonType(value)
+	for _, candidate := range expected {
+		switch {
+		case candidate == actual:
+			return true
+		case candidate == "number" && actual == "integer":
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType returns the JSON schema type name of a JSON-decoded value.
+func jsonType(value interface{}) string {
+	switch actual := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if actual == math.Trunc(actual) {
+			return "integer"
+		}
+		return "number"
+	case float32:
+		return "number"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "integer"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, candidate := range enum {
+		if fmt.Sprint(candidate) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package conversion
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	schema "github.com/viant/mcp-protocol/schema"
+)
+
+func TestValidateOutput(t *testing.T) {
+	const schemaJSON = `{
+		"type": "object",
+		"properties": {
+			"status": { "type": "string", "enum": ["ok", "failed"] },
+			"count":  { "type": "integer" },
+			"ratio":  { "type": "number" },
+			"tags":   { "type": "array", "items": { "type": "string" } },
+			"owner":  {
+				"type": "object",
+				"properties": { "id": { "type": "integer" } },
+				"required": ["id"]
+			}
+		},
+		"required": ["status"]
+	}`
+	testCases := []struct {
+		name        string
+		valueJSON   string
+		expectPaths []string
+	}{
+		{
+			name:      "valid",
+			valueJSON: `{"status":"ok","count":3,"ratio":1,"tags":["a"],"owner":{"id":1}}`,
+		},
+		{
+			name:        "missing required",
+			valueJSON:   `{"count":3}`,
+			expectPaths: []string{"status"},
+		},
+		{
+			name:        "type and enum mismatch",
+			valueJSON:   `{"status":"unknown","count":1.5,"tags":["a",2]}`,
+			expectPaths: []string{"count", "status", "tags[1]"},
+		},
+		{
+			name:        "nested required",
+			valueJSON:   `{"status":"ok","owner":{}}`,
+			expectPaths: []string{"owner.id"},
+		},
+	}
+
+	outputSchema := &schema.ToolOutputSchema{}
+	require.NoError(t, json.Unmarshal([]byte(schemaJSON), outputSchema))
+	for _, tc := range testCases {
+		var value map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(tc.valueJSON), &value), tc.name)
+		err := ValidateOutput(outputSchema, value)
+		if len(tc.expectPaths) == 0 {
+			assert.NoError(t, err, tc.name)
+			continue
+		}
+		validationErr, ok := err.(*ValidationError)
+		require.True(t, ok, tc.name)
+		var paths []string
+		for _, fieldErr := range validationErr.Errors {
+			paths = append(paths, fieldErr.Field)
+		}
+		assert.EqualValues(t, tc.expectPaths, paths, tc.name)
+	}
+}
+
+func TestValidateInput(t *testing.T) {
+	const schemaJSON = `{
+		"type": "object",
+		"properties": {
+			"name":   { "type": "string", "pattern": "^[a-z]+$", "maxLength": 8 },
+			"limit":  { "type": "integer", "minimum": 1, "maximum": 100 },
+			"since":  { "type": "string", "format": "date-time" },
+			"tags":   { "type": "array", "maxItems": 2, "items": { "type": "string" } },
+			"labels": { "type": "object", "additionalProperties": { "type": "string" } }
+		},
+		"required": ["name"]
+	}`
+	testCases := []struct {
+		description string
+		args        map[string]interface{}
+		expect      []FieldError
+	}{
+		{
+			description: "valid",
+			args:        map[string]interface{}{"name": "orders", "limit": 10, "since": "2024-01-02T03:04:05Z", "tags": []string{"a"}, "labels": map[string]string{"team": "ads"}},
+		},
+		{
+			description: "constraints",
+			args:        map[string]interface{}{"name": "Orders", "limit": 0, "since": "yesterday", "tags": []string{"a", "b", "c"}, "labels": map[string]interface{}{"team": 1}},
+			expect: []FieldError{
+				{Field: "labels.team", Message: "expected string, got integer"},
+				{Field: "limit", Message: "must be >= 1"},
+				{Field: "name", Message: "must match pattern ^[a-z]+$"},
+				{Field: "since", Message: `must be a date-time, got "yesterday"`},
+				{Field: "tags", Message: "must have at most 2 items"},
+			},
+		},
+		{
+			description: "missing and mistyped",
+			args:        map[string]interface{}{"limit": "ten"},
+			expect: []FieldError{
+				{Field: "limit", Message: "expected integer, got string"},
+				{Field: "name", Message: "is required"},
+			},
+		},
+	}
+
+	inputSchema := &schema.ToolInputSchema{}
+	require.NoError(t, json.Unmarshal([]byte(schemaJSON), inputSchema))
+	for _, testCase := range testCases {
+		err := ValidateInput(inputSchema, testCase.args)
+		if len(testCase.expect) == 0 {
+			assert.NoError(t, err, testCase.description)
+			continue
+		}
+		validationErr, ok := err.(*ValidationError)
+		if assert.True(t, ok, testCase.description) {
+			assert.EqualValues(t, testCase.expect, validationErr.Errors, testCase.description)
+		}
+	}
+}
+
+func TestValidateInputDocument(t *testing.T) {
+	const schemaJSON = `{
+		"type": "object",
+		"$defs": {
+			"id":   { "type": "integer", "minimum": 1 },
+			"node": { "type": "object", "properties": { "id": { "$ref": "#/$defs/id" }, "children": { "type": "array", "items": { "$ref": "#/$defs/node" } } }, "required": ["id"] }
+		},
+		"properties": {
+			"owner":  { "$ref": "#/$defs/id" },
+			"tree":   { "$ref": "#/$defs/node" },
+			"target": { "anyOf": [
+				{ "type": "object", "properties": { "id": { "$ref": "#/$defs/id" } }, "required": ["id"] },
+				{ "type": "object", "properties": { "email": { "type": "string", "pattern": "@" } }, "required": ["email"] }
+			] },
+			"limit":  { "oneOf": [ { "type": "integer", "maximum": 10 }, { "type": "integer", "minimum": 5 } ] },
+			"page":   { "allOf": [ { "type": "integer", "minimum": 1 }, { "minimum": 0, "maximum": 100 } ] }
+		},
+		"required": ["owner"]
+	}`
+	testCases := []struct {
+		description string
+		args        map[string]interface{}
+		expect      []FieldError
+	}{
+		{
+			description: "valid",
+			args: map[string]interface{}{"owner": 1, "limit": 2, "page": 100,
+				"tree":   map[string]interface{}{"id": 1, "children": []interface{}{map[string]interface{}{"id": 2}}},
+				"target": map[string]interface{}{"email": "a@b"}},
+		},
+		{
+			description: "referenced and recursive",
+			args:        map[string]interface{}{"owner": 0, "tree": map[string]interface{}{"id": 1, "children": []interface{}{map[string]interface{}{"id": "x"}, map[string]interface{}{}}}},
+			expect: []FieldError{
+				{Field: "owner", Message: "must be >= 1"},
+				{Field: "tree.children[0].id", Message: "expected integer, got string"},
+				{Field: "tree.children[1].id", Message: "is required"},
+			},
+		},
+		{
+			description: "composition",
+			args:        map[string]interface{}{"owner": 1, "target": map[string]interface{}{"email": "nobody"}, "limit": 7, "page": 0},
+			expect: []FieldError{
+				{Field: "limit", Message: "must match exactly one oneOf schema, matched 2"},
+				{Field: "page", Message: "must be >= 1"},
+				{Field: "target", Message: "must match at least one anyOf schema"},
+			},
+		},
+		{
+			description: "missing",
+			args:        map[string]interface{}{"limit": 20},
+			expect: []FieldError{
+				{Field: "owner", Message: "is required"},
+			},
+		},
+	}
+
+	var document map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(schemaJSON), &document))
+	for _, testCase := range testCases {
+		err := ValidateInputDocument(document, testCase.args)
+		if len(testCase.expect) == 0 {
+			assert.NoError(t, err, testCase.description)
+			continue
+		}
+		validationErr, ok := err.(*ValidationError)
+		if assert.True(t, ok, testCase.description) {
+			assert.EqualValues(t, testCase.expect, validationErr.Errors, testCase.description)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package tool
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// ErrorClassCircuitOpen covers calls rejected by an open circuit breaker.
+const ErrorClassCircuitOpen = "circuit_open"
+
+// CallError reports a failed upstream call with its error class. Execution
+// records keep errors as text only, so the class is part of the message and
+// recovered with ParseCallError.
+type CallError struct {
+	Class string
+	// Retryable reports whether calling again later may succeed.
+	Retryable bool
+	Err       error
+}
+
+// Error implements error.
+func (e *CallError) Error() string {
+	return fmt.Sprintf("[upstream %s] %v", e.Class, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *CallError) Unwrap() error { return e.Err }
+
+var callErrorExpr = regexp.MustCompile(`\[upstream (\w+)\] `)
+
+// newCallError classifies a failed upstream call; errors without a class,
+// e.g. cancellations, are returned unchanged.
+func newCallError(err error) error {
+	class := errorClass(err)
+	if errors.Is(err, ErrCircuitOpen) {
+		class = ErrorClassCircuitOpen
+	}
+	if class == "" {
+		return err
+	}
+	return &CallError{Class: class, Retryable: retryableClass(class), Err: err}
+}
+
+// retryableClass reports whether calls failing with class may succeed later.
+func retryableClass(class string) bool {
+	switch class {
+	case ErrorClassTransport, ErrorClassInternal, ErrorClassTimeout, ErrorClassCircuitOpen:
+		return true
+	}
+	return false
+}
+
+// ParseCallError recovers the CallError encoded in message, the text of an
+// execution error; the class marker is removed from the message.
+func ParseCallError(message string) (*CallError, bool) {
+	loc := callErrorExpr.FindStringSubmatchIndex(message)
+	if loc == nil {
+		return nil, false
+	}
+	class := message[loc[2]:loc[3]]
+	text := message[:loc[0]] + message[loc[1]:]
+	return &CallError{Class: class, Retryable: retryableClass(class), Err: errors.New(text)}, true
+}
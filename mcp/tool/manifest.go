@@ -0,0 +1,123 @@
+package tool
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/viant/afs"
+	mcpschema "github.com/viant/mcp-protocol/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest is a snapshot of the tools offered by an MCP server, in the shape
+// of a tools/list result. ETag identifies the tool set content and CreatedAt
+// records when it was listed. InputSchemas holds, by tool name, the complete
+// input schema of tools using keywords mcpschema.ToolInputSchema has no field
+// for, such as a root "$defs"; it is encoded in place of their inputSchema.
+type Manifest struct {
+	Tools        []mcpschema.Tool                  `json:"tools" yaml:"tools"`
+	InputSchemas map[string]map[string]interface{} `json:"-" yaml:"-"`
+	ETag         string                            `json:"etag,omitempty" yaml:"etag,omitempty"`
+	CreatedAt    time.Time                         `json:"createdAt,omitempty" yaml:"createdAt,omitempty"`
+}
+
+// MarshalJSON encodes the manifest with the complete input schemas.
+func (m *Manifest) MarshalJSON() ([]byte, error) {
+	type plain Manifest
+	data, err := json.Marshal((*plain)(m))
+	if err != nil || len(m.InputSchemas) == 0 {
+		return data, err
+	}
+	var raw map[string]interface{}
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	tools, _ := raw["tools"].([]interface{})
+	for _, item := range tools {
+		if tool, ok := item.(map[string]interface{}); ok {
+			name, _ := tool["name"].(string)
+			if document, ok := m.InputSchemas[name]; ok {
+				tool["inputSchema"] = document
+			}
+		}
+	}
+	return json.Marshal(raw)
+}
+
+// schemaFields are the input schema keywords mcpschema.ToolInputSchema keeps.
+var schemaFields = map[string]bool{"type": true, "properties": true, "required": true}
+
+// inputSchemas returns the complete input schemas of a decoded tools/list
+// result for tools whose schema uses keywords beyond schemaFields, or nil.
+func inputSchemas(result map[string]interface{}) map[string]map[string]interface{} {
+	var ret map[string]map[string]interface{}
+	tools, _ := result["tools"].([]interface{})
+	for _, item := range tools {
+		tool, _ := item.(map[string]interface{})
+		name, _ := tool["name"].(string)
+		document, _ := tool["inputSchema"].(map[string]interface{})
+		for keyword := range document {
+			if schemaFields[keyword] {
+				continue
+			}
+			if ret == nil {
+				ret = make(map[string]map[string]interface{})
+			}
+			ret[name] = document
+			break
+		}
+	}
+	return ret
+}
+
+// ETag returns a content hash of tools; it changes whenever a tool is added,
+// removed or modified.
+func ETag(tools []mcpschema.Tool) string {
+	data, _ := json.Marshal(tools)
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// LoadManifest reads a JSON or YAML manifest from a file or afs URL. Both a
+// tools/list result object and a bare list of tools are accepted.
+func LoadManifest(ctx context.Context, URL string) (*Manifest, error) {
+	data, err := afs.New().DownloadWithURL(ctx, URL)
+	if err != nil {
+		return nil, fmt.Errorf("download tool manifest %q: %w", URL, err)
+	}
+	ret, err := decodeManifest(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse tool manifest %q: %w", URL, err)
+	}
+	return ret, nil
+}
+
+// decodeManifest goes through a generic YAML value (YAML is a superset of
+// JSON) so that the JSON field names of mcpschema.Tool apply to both formats.
+func decodeManifest(data []byte) (*Manifest, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if list, ok := raw.([]interface{}); ok {
+		raw = map[string]interface{}{"tools": list}
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	ret := &Manifest{}
+	if err := json.Unmarshal(data, ret); err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	ret.InputSchemas = inputSchemas(result)
+	return ret, nil
+}
@@ -0,0 +1,54 @@
+package tool
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadManifest(t *testing.T) {
+	testCases := []struct {
+		description string
+		file        string
+		content     string
+	}{
+		{
+			description: "json tools/list result",
+			file:        "manifest.json",
+			content:     `{"tools":[{"name":"query","description":"Run a query","inputSchema":{"type":"object","properties":{"sql":{"type":"string"}},"required":["sql"]}}]}`,
+		},
+		{
+			description: "yaml list",
+			file:        "manifest.yaml",
+			content: `- name: query
+  description: Run a query
+  inputSchema:
+    type: object
+    properties:
+      sql: {type: string}
+    required: [sql]
+`,
+		},
+	}
+	for _, testCase := range testCases {
+		location := filepath.Join(t.TempDir(), testCase.file)
+		if !assert.NoError(t, os.WriteFile(location, []byte(testCase.content), 0644), testCase.description) {
+			continue
+		}
+		manifest, err := LoadManifest(context.Background(), location)
+		if !assert.NoError(t, err, testCase.description) || !assert.Len(t, manifest.Tools, 1, testCase.description) {
+			continue
+		}
+		actual := manifest.Tools[0]
+		assert.EqualValues(t, "query", actual.Name, testCase.description)
+		assert.EqualValues(t, "Run a query", *actual.Description, testCase.description)
+		assert.EqualValues(t, []string{"sql"}, actual.InputSchema.Required, testCase.description)
+		assert.EqualValues(t, "string", actual.InputSchema.Properties["sql"]["type"], testCase.description)
+	}
+
+	_, err := LoadManifest(context.Background(), filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
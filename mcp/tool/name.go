@@ -1,6 +1,10 @@
 package tool
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/viant/fluxor-mcp/mcp/matcher"
+)
 
 // Name represents tool name
 type Name string
@@ -82,3 +86,56 @@ func (t Name) String() string {
 func NewName(service, name string) Name {
 	return Name(strings.ReplaceAll(service, "/", "_") + "-" + name)
 }
+
+// Path returns the slash separated form of the tool name used for pattern
+// matching: service path and method joined by "/", e.g. system/exec/execute.
+func (t Name) Path() string {
+	if method := t.Method(); method != "" {
+		return t.Service() + "/" + method
+	}
+	return strings.ReplaceAll(string(t), "_", "/")
+}
+
+// Pattern converts a tool name pattern written in slash ("system/exec/*") or
+// canonical ("system_exec-execute") notation into the slash form matched
+// against Name.Path. Slash patterns are kept verbatim; in canonical patterns
+// the last dash separates the method, which is kept as is, from the service
+// path whose underscores become slashes. Negations are preserved and "re:"
+// expressions are kept verbatim.
+func Pattern(pattern string) string {
+	if strings.HasPrefix(pattern, "!") {
+		return "!" + Pattern(pattern[1:])
+	}
+	if !isNamePattern(pattern) {
+		return pattern
+	}
+	if idx := strings.LastIndex(pattern, "-"); idx != -1 {
+		return strings.ReplaceAll(pattern[:idx], "_", "/") + "/" + pattern[idx+1:]
+	}
+	return strings.ReplaceAll(pattern, "_", "/")
+}
+
+// isNamePattern reports whether pattern uses the canonical tool name notation.
+func isNamePattern(pattern string) bool {
+	return pattern != "*" && !strings.HasPrefix(pattern, "re:") && !strings.Contains(pattern, "/")
+}
+
+// Match reports whether the tool name satisfies pattern (see matcher.Match).
+// Canonical patterns also match the name as is, so that a dash or underscore
+// within a service or method name never has to be spelled as a slash.
+func Match(pattern, name string) bool {
+	if strings.HasPrefix(pattern, "!") {
+		return !Match(pattern[1:], name)
+	}
+	if matcher.Match(Pattern(pattern), Name(name).Path()) {
+		return true
+	}
+	return isNamePattern(pattern) && matcher.Match(pattern, name)
+}
+
+// MatchAny evaluates tool name patterns in order (see matcher.MatchAny).
+func MatchAny(patterns []string, name string) bool {
+	return matcher.MatchAnyFunc(patterns, func(pattern string) bool {
+		return Match(pattern, name)
+	})
+}
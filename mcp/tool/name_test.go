@@ -19,3 +19,42 @@ func TestCanonical(t *testing.T) {
 		}
 	}
 }
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern string
+		name    string
+		matched bool
+	}{
+		{"*", "system_exec-execute", true},
+		{"system/", "system_exec-execute", true},
+		{"system_exec", "system_exec-execute", true},
+		{"system_exec-execute", "system_exec-execute", true},
+		{"printer/*", "printer-print", true},
+		{"printer/*", "system_exec-execute", false},
+		{"system/*/execute", "system_exec-execute", true},
+		{"system/**", "system_exec-execute", true},
+		{"re:^system/(exec|patch)/", "system_patch-apply", true},
+		{"!system/exec", "system_exec-execute", false},
+		{"!system/exec", "system_patch-apply", true},
+		{"my-server/*", "my-server-lookup", true},
+		{"my-server/*", "my-lookup", false},
+		{"my-server", "my-server-lookup", true},
+		{"system/exec/run_cmd", "system_exec-run_cmd", true},
+		{"system_exec-run_cmd", "system_exec-run_cmd", true},
+		{"system_exec-run_*", "system_exec-run_cmd", true},
+		{"!my-server/*", "my-server-lookup", false},
+	}
+
+	for i, tc := range cases {
+		if got := Match(tc.pattern, tc.name); got != tc.matched {
+			t.Fatalf("case %d: Match(%q, %q) = %v, want %v", i, tc.pattern, tc.name, got, tc.matched)
+		}
+	}
+	if !MatchAny([]string{"system/", "!system/exec"}, "system_patch-apply") || MatchAny([]string{"system/", "!system/exec"}, "system_exec-execute") {
+		t.Fatalf("MatchAny: expected all of system except system/exec")
+	}
+	if MatchAny([]string{"my-server/", "!my-server/delete"}, "my-server-delete") || !MatchAny([]string{"my-server/", "!my-server/delete"}, "my-server-lookup") {
+		t.Fatalf("MatchAny: expected all of my-server except delete")
+	}
+}
@@ -0,0 +1,115 @@
+package tool
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"regexp"
+	"sync"
+)
+
+// DefaultMaxNameLength is the tool name limit enforced by strict MCP hosts.
+const DefaultMaxNameLength = 64
+
+const hashSuffixLength = 8
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// Naming maps canonical tool names to names accepted by strict MCP hosts
+// (^[a-zA-Z0-9_-]+$, limited length) and back. Valid names are kept as is.
+// Otherwise invalid characters are replaced with "_", the result is truncated
+// to fit MaxLength and suffixed with a hash of the canonical name, so the
+// issued name depends on the canonical name alone and stays unique and stable
+// whatever the registration order. Every issued name is recorded so that it
+// can be resolved to the canonical one.
+//
+// A nil *Naming leaves names unchanged.
+type Naming struct {
+	MaxLength   int
+	mu          sync.RWMutex
+	toExternal  map[string]string
+	toCanonical map[string]string
+}
+
+// NewNaming creates a naming strategy; zero maxLength uses
+// DefaultMaxNameLength.
+func NewNaming(maxLength int) *Naming {
+	if maxLength <= 0 {
+		maxLength = DefaultMaxNameLength
+	}
+	if maxLength <= hashSuffixLength+1 {
+		maxLength = hashSuffixLength + 2
+	}
+	return &Naming{
+		MaxLength:   maxLength,
+		toExternal:  map[string]string{},
+		toCanonical: map[string]string{},
+	}
+}
+
+// Name returns the external name of a canonical tool name.
+func (n *Naming) Name(canonical string) string {
+	if n == nil {
+		return canonical
+	}
+	n.mu.RLock()
+	external, ok := n.toExternal[canonical]
+	n.mu.RUnlock()
+	if ok {
+		return external
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if external, ok = n.toExternal[canonical]; ok {
+		return external
+	}
+	external = n.external(canonical)
+	n.toExternal[canonical] = external
+	n.toCanonical[external] = canonical
+	return external
+}
+
+// external derives the external name of a canonical name.
+func (n *Naming) external(canonical string) string {
+	if len(canonical) <= n.MaxLength && !invalidNameChars.MatchString(canonical) {
+		return canonical
+	}
+	external := invalidNameChars.ReplaceAllString(canonical, "_")
+	if limit := n.MaxLength - hashSuffixLength - 1; len(external) > limit {
+		external = external[:limit]
+	}
+	return external + "_" + nameHash(canonical)
+}
+
+// Canonical resolves an issued external name or a known canonical name to
+// the canonical name. Unknown names are returned unchanged with false.
+func (n *Naming) Canonical(name string) (string, bool) {
+	if n == nil {
+		return name, true
+	}
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if canonical, ok := n.toCanonical[name]; ok {
+		return canonical, true
+	}
+	_, ok := n.toExternal[name]
+	return name, ok
+}
+
+// Mapping returns a copy of the external → canonical name table.
+func (n *Naming) Mapping() map[string]string {
+	result := map[string]string{}
+	if n == nil {
+		return result
+	}
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	for external, canonical := range n.toCanonical {
+		result[external] = canonical
+	}
+	return result
+}
+
+func nameHash(name string) string {
+	sum := sha1.Sum([]byte(name))
+	return hex.EncodeToString(sum[:])[:hashSuffixLength]
+}
@@ -0,0 +1,47 @@
+package tool
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNaming(t *testing.T) {
+	long := "analytics_" + strings.Repeat("warehouse_", 8) + "reports-query"
+	cases := []struct {
+		description string
+		canonical   string
+		expected    string
+	}{
+		{description: "valid name unchanged", canonical: "system_exec-execute", expected: "system_exec-execute"},
+		{description: "invalid characters get hash", canonical: "docs-search.v2", expected: "docs-search_v2_" + nameHash("docs-search.v2")},
+		{description: "same sanitized name gets own hash", canonical: "docs-search/v2", expected: "docs-search_v2_" + nameHash("docs-search/v2")},
+		{description: "long name truncated", canonical: long, expected: long[:64-hashSuffixLength-1] + "_" + nameHash(long)},
+	}
+
+	naming := NewNaming(0)
+	for _, tc := range cases {
+		actual := naming.Name(tc.canonical)
+		assert.EqualValues(t, tc.expected, actual, tc.description)
+		assert.LessOrEqual(t, len(actual), DefaultMaxNameLength, tc.description)
+		assert.Regexp(t, `^[a-zA-Z0-9_-]+$`, actual, tc.description)
+		assert.EqualValues(t, actual, naming.Name(tc.canonical), tc.description+" stable")
+		for _, name := range []string{actual, tc.canonical} {
+			canonical, ok := naming.Canonical(name)
+			assert.True(t, ok, tc.description)
+			assert.EqualValues(t, tc.canonical, canonical, tc.description)
+		}
+	}
+	_, ok := naming.Canonical("unknown-tool")
+	assert.False(t, ok)
+	assert.Len(t, naming.Mapping(), len(cases))
+
+	reversed := NewNaming(0)
+	for i := len(cases) - 1; i >= 0; i-- {
+		assert.EqualValues(t, cases[i].expected, reversed.Name(cases[i].canonical), cases[i].description+" order independent")
+	}
+
+	var disabled *Naming
+	assert.EqualValues(t, "docs-search.v2", disabled.Name("docs-search.v2"))
+}
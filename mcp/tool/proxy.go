@@ -9,11 +9,15 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/viant/fluxor-mcp/internal/conv"
+	"github.com/viant/fluxor-mcp/mcp/config"
 	mcontext "github.com/viant/fluxor-mcp/mcp/context"
 	"github.com/viant/fluxor-mcp/mcp/tool/conversion"
 	"github.com/viant/fluxor/model/types"
+	"github.com/viant/jsonrpc"
 	mcpschema "github.com/viant/mcp-protocol/schema"
 	"github.com/viant/mcp/client"
 	mcpclient "github.com/viant/mcp/client"
@@ -39,55 +43,294 @@ import (
 // Proxy definition & registry refresh
 // -----------------------------------------------------------------------------
 
+// MethodNotificationToolsListChanged is the notification an MCP server sends
+// whenever the set of tools it offers changes.
+const MethodNotificationToolsListChanged = "notifications/tools/list_changed"
+
+// RawToolLister is implemented by MCP clients that return the tools/list
+// result as sent by the server. Unlike ListTools, it keeps input schema
+// keywords mcpschema.ToolInputSchema has no field for, such as a root "$defs"
+// that properties refer to; it returns errors.ErrUnsupported when it cannot
+// list tools that way.
+type RawToolLister interface {
+	ListToolsJSON(ctx context.Context, cursor *string) (json.RawMessage, error)
+}
+
 type Proxy struct {
-	name    string
-	client  mcpclient.Interface
-	methods map[string]*mcpschema.Tool
-	sigs    types.Signatures
+	name      string
+	client    mcpclient.Interface
+	methods   map[string]*mcpschema.Tool
+	presets   map[string]map[string]*Preset
+	sigs      types.Signatures
+	listeners []func(ctx context.Context)
+	overrides map[string]*Override
+	prefix    string
+	retry     *RetryPolicy
+	breaker   *breaker
+	manifest  *Manifest
+	tools     []mcpschema.Tool
+	schemas   map[string]map[string]interface{}
+	etag      string
+	loadedAt  time.Time
+	// credentials resolves the upstream auth token per call (nil – none).
+	credentials func(ctx context.Context) (string, error)
+	// generation orders refreshes: a tool list is applied only when no
+	// later refresh has been applied already.
+	generation atomic.Uint64
+	loaded     uint64
+	// refreshing and pending coalesce refreshes triggered by notifications.
+	refreshing bool
+	pending    bool
+	done       context.Context
+	stop       context.CancelFunc
 	sync.Mutex
 }
 
-// NewProxy creates a new tool proxy and immediately discovers the server's
-// tool registry. Call `proxy.refresh(ctx)` later if you need to pick up tools
-// added after start‑up.
-func NewProxy(ctx context.Context, name string, cli mcpclient.Interface) (types.Service, error) {
+// notificationRefreshTimeout bounds a refresh triggered by a notification.
+const notificationRefreshTimeout = time.Minute
+
+// Override customises how an upstream tool is exposed by a Proxy (see
+// config.Override).
+type Override = config.Override
+
+// Preset is an argument value applied by the proxy (see config.Preset).
+type Preset = config.Preset
+
+// ProxyOption customises a Proxy.
+type ProxyOption func(*Proxy)
+
+// WithOverrides applies per-tool overrides keyed by the upstream tool name.
+func WithOverrides(overrides map[string]*Override) ProxyOption {
+	return func(p *Proxy) {
+		p.overrides = overrides
+	}
+}
+
+// WithPrefix prepends prefix to every exposed tool name, after renaming.
+func WithPrefix(prefix string) ProxyOption {
+	return func(p *Proxy) {
+		p.prefix = prefix
+	}
+}
+
+// WithRetry sets the retry policy of upstream calls; tool overrides can
+// replace it per tool.
+func WithRetry(policy *RetryPolicy) ProxyOption {
+	return func(p *Proxy) {
+		p.retry = policy
+	}
+}
+
+// WithCircuitBreaker makes calls fail fast while the upstream keeps failing.
+func WithCircuitBreaker(policy *BreakerPolicy) ProxyOption {
+	return func(p *Proxy) {
+		if policy != nil {
+			p.breaker = newBreaker(policy)
+		}
+	}
+}
+
+// WithCredentials sets the function resolving the auth token sent with each
+// call, e.g. from the caller of a served tool; an empty token sends none. It
+// takes precedence over a token attached with mcontext.WithAuthToken.
+func WithCredentials(credentials func(ctx context.Context) (string, error)) ProxyOption {
+	return func(p *Proxy) {
+		p.credentials = credentials
+	}
+}
+
+// WithManifest builds the initial signatures from a tool manifest instead of
+// listing tools, so that the proxy can be created without connecting.
+func WithManifest(manifest *Manifest) ProxyOption {
+	return func(p *Proxy) {
+		p.manifest = manifest
+	}
+}
+
+// NewProxy creates a new tool proxy and discovers the server's tools unless
+// a manifest is supplied with WithManifest; see Refresh for later changes.
+func NewProxy(ctx context.Context, name string, cli mcpclient.Interface, options ...ProxyOption) (*Proxy, error) {
 	name = strings.ReplaceAll(name, "_", "/")
 	p := &Proxy{name: name, client: cli}
+	p.done, p.stop = context.WithCancel(context.Background())
+	for _, option := range options {
+		option(p)
+	}
+	if p.manifest != nil {
+		if err := p.load(p.generation.Add(1), p.manifest.Tools, p.manifest.InputSchemas); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
 	if err := p.refresh(ctx); err != nil {
 		return nil, err
 	}
 	return p, nil
 }
 
-// refresh (re)hydrates the local tool registry.
-func (p *Proxy) refresh(ctx context.Context) error {
+// Refresh re-runs tool discovery and replaces the proxy signatures. As the
+// proxy itself is the service registered with Fluxor, the action registry
+// reflects the new signatures as soon as Refresh returns. Registered OnChange
+// listeners are notified after every successful refresh, whether or not the
+// tool set changed; compare Manifest().ETag to detect a change.
+func (p *Proxy) Refresh(ctx context.Context) error {
+	if err := p.refresh(ctx); err != nil {
+		return err
+	}
+	p.Lock()
+	listeners := append([]func(ctx context.Context){}, p.listeners...)
+	p.Unlock()
+	for _, listener := range listeners {
+		listener(ctx)
+	}
+	return nil
+}
+
+// OnChange registers a callback invoked after every successful Refresh.
+func (p *Proxy) OnChange(listener func(ctx context.Context)) {
+	p.Lock()
+	defer p.Unlock()
+	p.listeners = append(p.listeners, listener)
+}
+
+// OnNotification reacts to server-initiated notifications routed from the
+// client handler of the proxied connection. Tool list changes trigger an
+// asynchronous Refresh – notifications are delivered on the transport reader,
+// so issuing a blocking ListTools call from here could dead-lock it. A burst
+// of notifications results in one refresh running at a time plus, at most,
+// one more once it completes.
+func (p *Proxy) OnNotification(_ context.Context, notification *jsonrpc.Notification) {
+	if notification == nil || notification.Method != MethodNotificationToolsListChanged {
+		return
+	}
 	p.Lock()
 	defer p.Unlock()
+	if p.done.Err() != nil {
+		return
+	}
+	p.pending = true
+	if !p.refreshing {
+		p.refreshing = true
+		go p.refreshPending()
+	}
+}
+
+// refreshPending refreshes until no notification is pending or the proxy is
+// closed.
+func (p *Proxy) refreshPending() {
+	for {
+		p.Lock()
+		if !p.pending || p.done.Err() != nil {
+			p.refreshing = false
+			p.Unlock()
+			return
+		}
+		p.pending = false
+		p.Unlock()
+		ctx, cancel := context.WithTimeout(p.done, notificationRefreshTimeout)
+		_ = p.Refresh(ctx)
+		cancel()
+	}
+}
+
+// Close stops refreshes in progress and ignores later notifications.
+func (p *Proxy) Close() {
+	p.stop()
+}
+
+// refresh (re)hydrates the local tool registry. Remote discovery runs outside
+// the lock so that in-flight calls are not blocked by a slow server.
+func (p *Proxy) refresh(ctx context.Context) error {
+	generation := p.generation.Add(1)
 	var (
-		tools  []mcpschema.Tool
-		cursor *string
+		tools   []mcpschema.Tool
+		schemas map[string]map[string]interface{}
+		cursor  *string
 	)
 	for {
-		res, err := p.client.ListTools(ctx, cursor)
+		res, pageSchemas, err := p.listTools(ctx, cursor)
 		if err != nil {
 			return fmt.Errorf("list tools: %w", err)
 		}
 		tools = append(tools, res.Tools...)
+		for name, document := range pageSchemas {
+			if schemas == nil {
+				schemas = make(map[string]map[string]interface{})
+			}
+			schemas[name] = document
+		}
 		if res.NextCursor == nil || *res.NextCursor == "" {
 			break
 		}
 		cursor = res.NextCursor
 	}
+	return p.load(generation, tools, schemas)
+}
 
-	p.methods = make(map[string]*mcpschema.Tool, len(tools))
+// listTools lists a page of tools along with their complete input schemas
+// when the client is a RawToolLister.
+func (p *Proxy) listTools(ctx context.Context, cursor *string) (*mcpschema.ListToolsResult, map[string]map[string]interface{}, error) {
+	lister, ok := p.client.(RawToolLister)
+	if !ok {
+		res, err := p.client.ListTools(ctx, cursor)
+		return res, nil, err
+	}
+	data, err := lister.ListToolsJSON(ctx, cursor)
+	if errors.Is(err, errors.ErrUnsupported) {
+		res, err := p.client.ListTools(ctx, cursor)
+		return res, nil, err
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	res := &mcpschema.ListToolsResult{}
+	if err = json.Unmarshal(data, res); err != nil {
+		return nil, nil, err
+	}
+	var result map[string]interface{}
+	if err = json.Unmarshal(data, &result); err != nil {
+		return nil, nil, err
+	}
+	return res, inputSchemas(result), nil
+}
+
+// Manifest returns the upstream tools the proxy signatures were built from.
+func (p *Proxy) Manifest() *Manifest {
+	p.Lock()
+	defer p.Unlock()
+	return &Manifest{Tools: p.tools, InputSchemas: p.schemas, ETag: p.etag, CreatedAt: p.loadedAt}
+}
+
+// load replaces the proxy signatures with the ones of tools, converting the
+// complete input schema from schemas where a tool has one, unless a later
+// generation has been loaded already. Tool overrides and the name prefix are
+// applied here, so the exposed names map back to upstream tools through
+// methods. Tools exposed under the same name are reported as an error and
+// leave the loaded signatures unchanged.
+func (p *Proxy) load(generation uint64, tools []mcpschema.Tool, schemas map[string]map[string]interface{}) error {
+	etag := ETag(tools)
+	methods := make(map[string]*mcpschema.Tool, len(tools))
+	presets := make(map[string]map[string]*Preset)
 	sigs := make(types.Signatures, 0, len(tools))
 
 	for i, t := range tools {
 		tool := t // capture
-		p.methods[tool.Name] = &tools[i]
+		name, description, hidden := p.expose(&tool)
+		if hidden {
+			continue
+		}
+		if other, ok := methods[name]; ok {
+			return fmt.Errorf("tools %q and %q are both exposed as %q", other.Name, tool.Name, name)
+		}
+		methods[name] = &tools[i]
+		inputSchema := tool.InputSchema
+		if override := p.overrides[tool.Name]; override != nil && len(override.Presets) > 0 {
+			presets[name] = override.Presets
+			inputSchema = applyPresets(inputSchema, override.Presets)
+		}
 
 		// ---------------- schema → reflect types ---------------- //
-		inT, _ := conversion.TypeFromInputSchema(tool.InputSchema)
+		inT, _ := conversion.TypeFromSchema(inputDocument(inputSchema, schemas[tool.Name]))
 		if inT == nil {
 			inT = reflect.TypeOf(map[string]any{})
 		}
@@ -101,29 +344,255 @@ func (p *Proxy) refresh(ctx context.Context) error {
 		}
 
 		sig := types.Signature{
-			Name:        tool.Name,
-			Description: conv.Dereference[string](tool.Description),
+			Name:        name,
+			Description: description,
 			Input:       inT,
 			Output:      outT,
 		}
 		sigs = append(sigs, sig)
 	}
+	p.Lock()
+	defer p.Unlock()
+	if generation < p.loaded {
+		return nil // superseded by a refresh that listed tools later
+	}
+	p.loaded = generation
+	p.methods = methods
+	p.presets = presets
 	p.sigs = sigs
+	p.tools = tools
+	p.schemas = schemas
+	p.etag = etag
+	p.loadedAt = time.Now()
 	return nil
 }
 
+// inputDocument returns the schema document of an input schema: a copy of
+// the complete document, if any, with the properties and required list of
+// inputSchema, which reflect presets.
+func inputDocument(inputSchema mcpschema.ToolInputSchema, document map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{}, len(document)+3)
+	for keyword, value := range document {
+		ret[keyword] = value
+	}
+	ret["type"] = "object"
+	ret["properties"] = map[string]map[string]interface{}(inputSchema.Properties)
+	if len(inputSchema.Required) > 0 {
+		ret["required"] = inputSchema.Required
+	} else {
+		delete(ret, "required")
+	}
+	return ret
+}
+
+// applyPresets returns a copy of the input schema without locked preset
+// arguments; unlocked presets become optional arguments with a default.
+func applyPresets(inputSchema mcpschema.ToolInputSchema, presets map[string]*Preset) mcpschema.ToolInputSchema {
+	properties := make(map[string]map[string]interface{}, len(inputSchema.Properties))
+	for k, v := range inputSchema.Properties {
+		properties[k] = v
+	}
+	var required []string
+	for _, name := range inputSchema.Required {
+		if presets[name] == nil {
+			required = append(required, name)
+		}
+	}
+	for name, preset := range presets {
+		if preset == nil {
+			continue
+		}
+		if preset.Locked {
+			delete(properties, name)
+			continue
+		}
+		if def, ok := properties[name]; ok {
+			withDefault := make(map[string]interface{}, len(def)+1)
+			for k, v := range def {
+				withDefault[k] = v
+			}
+			withDefault["default"] = preset.Value
+			properties[name] = withDefault
+		}
+	}
+	inputSchema.Properties = properties
+	inputSchema.Required = required
+	return inputSchema
+}
+
+// Presetter is implemented by services that apply preset arguments. Callers
+// holding raw call arguments merge presets before the arguments are encoded
+// into the method input type, so explicit values are never mistaken for
+// omitted ones.
+type Presetter interface {
+	// WithPresets returns args merged with the presets of a method.
+	WithPresets(method string, args map[string]interface{}) map[string]interface{}
+}
+
+// WithPresets merges presets of the exposed tool into call arguments: locked
+// presets always win, others fill arguments that were not supplied. It
+// implements Presetter.
+func (p *Proxy) WithPresets(name string, args map[string]interface{}) map[string]interface{} {
+	p.Lock()
+	presets := p.presets[name]
+	p.Unlock()
+	if len(presets) == 0 {
+		return args
+	}
+	merged := make(map[string]interface{}, len(args)+len(presets))
+	for k, v := range args {
+		merged[k] = v
+	}
+	for k, preset := range presets {
+		if preset == nil {
+			continue
+		}
+		if _, supplied := merged[k]; preset.Locked || !supplied || merged[k] == nil {
+			merged[k] = preset.Value
+		}
+	}
+	return merged
+}
+
+// InputDocumenter is implemented by services whose methods have an input
+// schema of their own, such as upstream tools. Callers validate arguments,
+// once presets are merged, against that document rather than the schema
+// built from the method input type, which flattens oneOf/anyOf and cannot
+// express mixed alternatives.
+type InputDocumenter interface {
+	// InputDocument returns the input schema document of a method or nil.
+	InputDocument(method string) map[string]interface{}
+}
+
+// InputDocument returns the complete input schema advertised by the upstream
+// tool, including keywords of the document root such as "$defs"; it
+// implements InputDocumenter.
+func (p *Proxy) InputDocument(name string) map[string]interface{} {
+	p.Lock()
+	defer p.Unlock()
+	tool, ok := p.methods[name]
+	if !ok {
+		return nil
+	}
+	return inputDocument(tool.InputSchema, p.schemas[tool.Name])
+}
+
+// expose returns the exposed name and description of an upstream tool, and
+// whether it is hidden.
+func (p *Proxy) expose(tool *mcpschema.Tool) (string, string, bool) {
+	name, description := tool.Name, conv.Dereference[string](tool.Description)
+	if override := p.overrides[tool.Name]; override != nil {
+		if override.Hidden {
+			return "", "", true
+		}
+		if override.Name != "" {
+			name = override.Name
+		}
+		if override.Description != "" {
+			description = override.Description
+		}
+	}
+	return p.prefix + name, description, false
+}
+
 // Name returns the service name.
 func (p *Proxy) Name() string { return p.name }
 
 // Methods returns all discovered tool signatures.
-func (p *Proxy) Methods() types.Signatures { return p.sigs }
+func (p *Proxy) Methods() types.Signatures {
+	p.Lock()
+	defer p.Unlock()
+	return p.sigs
+}
+
+// CircuitState returns the circuit breaker state, if a breaker is configured.
+func (p *Proxy) CircuitState() (CircuitState, bool) {
+	if p.breaker == nil {
+		return CircuitState{}, false
+	}
+	return p.breaker.state(), true
+}
+
+// Annotations returns the annotations advertised by the upstream tool; it
+// implements Annotator.
+func (p *Proxy) Annotations(method string) *Annotations {
+	tool, ok := p.lookup(method)
+	if !ok {
+		return nil
+	}
+	return AnnotationsFromSchema(tool.Annotations)
+}
+
+// retryPolicy returns the retry policy of an upstream tool.
+func (p *Proxy) retryPolicy(upstream string) *RetryPolicy {
+	if override := p.overrides[upstream]; override != nil && override.Retry != nil {
+		return override.Retry
+	}
+	return p.retry
+}
+
+// call invokes the upstream tool applying the retry policy and circuit
+// breaker. Errors carry the attempt count and breaker state and are
+// classified as *CallError when their class is known.
+func (p *Proxy) call(ctx context.Context, aClient mcpclient.Interface, params *mcpschema.CallToolRequestParams, options ...client.RequestOption) (*mcpschema.CallToolResult, error) {
+	policy := p.retryPolicy(params.Name)
+	attempts := retryAttempts(policy)
+	var (
+		res     *mcpschema.CallToolResult
+		err     error
+		attempt int
+	)
+	for attempt = 1; ; attempt++ {
+		if err = p.breaker.allow(); err != nil {
+			break
+		}
+		res, err = aClient.CallTool(ctx, params, options...)
+		p.breaker.record(err)
+		class := errorClass(err)
+		if err == nil {
+			if res.IsError == nil || !*res.IsError {
+				return res, nil
+			}
+			class = ErrorClassTool
+		}
+		if attempt >= attempts || !retryOn(policy, class) || ctx.Err() != nil {
+			break
+		}
+		if sleepErr := sleep(ctx, retryBackoff(policy, attempt)); sleepErr != nil {
+			break
+		}
+	}
+	if err == nil {
+		return res, nil // tool error result – reported by the caller
+	}
+	var details []string
+	if attempts > 1 {
+		details = append(details, fmt.Sprintf("attempt %d/%d", attempt, attempts))
+	}
+	if p.breaker != nil {
+		details = append(details, p.breaker.state().String())
+	}
+	if len(details) > 0 {
+		return nil, newCallError(fmt.Errorf("call tool %q (%s): %w", params.Name, strings.Join(details, ", "), err))
+	}
+	return nil, newCallError(fmt.Errorf("call tool %q: %w", params.Name, err))
+}
+
+// lookup returns the discovered tool definition by name.
+func (p *Proxy) lookup(name string) (*mcpschema.Tool, bool) {
+	p.Lock()
+	defer p.Unlock()
+	tool, ok := p.methods[name]
+	return tool, ok
+}
 
 // Method returns an executable for the requested tool.
 func (p *Proxy) Method(name string) (types.Executable, error) {
-	if len(p.methods) == 0 {
+	tool, ok := p.lookup(name)
+	if !ok && len(p.Methods()) == 0 {
 		_ = p.refresh(context.Background())
+		tool, ok = p.lookup(name)
 	}
-	tool, ok := p.methods[name]
 	if !ok {
 		return nil, types.NewMethodNotFoundError(name)
 	}
@@ -131,6 +600,7 @@ func (p *Proxy) Method(name string) (types.Executable, error) {
 	exec := func(ctx context.Context, input, output interface{}) error {
 		// ---------- invoke remote tool ---------- //
 		args, _ := conv.ToMap(input)
+		args = p.WithPresets(name, args)
 		var options []client.RequestOption
 
 		aClient := p.client
@@ -140,12 +610,21 @@ func (p *Proxy) Method(name string) (types.Executable, error) {
 		if value, ok := mcontext.AuthToken(ctx); ok {
 			options = append(options, client.WithAuthToken(value))
 		}
-		res, err := aClient.CallTool(ctx, &mcpschema.CallToolRequestParams{
+		if p.credentials != nil {
+			token, err := p.credentials(ctx)
+			if err != nil {
+				return fmt.Errorf("call tool %q: %w", tool.Name, err)
+			}
+			if token != "" {
+				options = append(options, client.WithAuthToken(token))
+			}
+		}
+		res, err := p.call(ctx, aClient, &mcpschema.CallToolRequestParams{
 			Name:      tool.Name,
 			Arguments: args,
 		}, options...)
 		if err != nil {
-			return fmt.Errorf("call tool %q: %w", tool.Name, err)
+			return err
 		}
 		if res.IsError != nil && *res.IsError {
 			return toolError(res)
@@ -2,11 +2,20 @@ package tool_test
 
 import (
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/viant/fluxor-mcp/internal/conv"
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
 	coretool "github.com/viant/fluxor-mcp/mcp/tool"
+	"github.com/viant/fluxor-mcp/mcp/tool/conversion"
 
 	"github.com/viant/jsonrpc"
 	transport "github.com/viant/jsonrpc/transport"
@@ -167,3 +176,570 @@ func TestRemoteToolService_StructuredContent(t *testing.T) {
 		t.Fatalf("unexpected message: %s", dst.Message)
 	}
 }
+
+// fakeClient is a minimal in-memory MCP client whose tool list can be
+// changed at runtime. Only the operations used by the proxy are implemented.
+type fakeClient struct {
+	mcpclient.Interface
+	mu    sync.Mutex
+	tools []mcpschema.Tool
+	args  map[string]interface{} // arguments of the last call
+	errs  []error                // errors returned by the next calls
+	calls int
+	token string // auth token of the last call
+}
+
+func (f *fakeClient) setTools(names ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tools = nil
+	for _, name := range names {
+		f.tools = append(f.tools, mcpschema.Tool{
+			Name: name,
+			InputSchema: mcpschema.ToolInputSchema{
+				Type:       "object",
+				Properties: map[string]map[string]interface{}{"message": {"type": "string"}},
+			},
+		})
+	}
+}
+
+func (f *fakeClient) ListTools(_ context.Context, _ *string, _ ...mcpclient.RequestOption) (*mcpschema.ListToolsResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &mcpschema.ListToolsResult{Tools: append([]mcpschema.Tool{}, f.tools...)}, nil
+}
+
+func (f *fakeClient) CallTool(_ context.Context, params *mcpschema.CallToolRequestParams, options ...mcpclient.RequestOption) (*mcpschema.CallToolResult, error) {
+	f.mu.Lock()
+	f.args = params.Arguments
+	f.token = ""
+	if requestOptions := mcpclient.NewRequestOptions(options); requestOptions != nil {
+		f.token = requestOptions.StringToken
+	}
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		f.mu.Unlock()
+		return nil, err
+	}
+	f.mu.Unlock()
+	return &mcpschema.CallToolResult{Content: []mcpschema.CallToolResultContentElem{{
+		Type: "text",
+		Text: params.Name,
+	}}}, nil
+}
+
+func TestProxy_RefreshOnToolsListChanged(t *testing.T) {
+	ctx := context.Background()
+	cli := &fakeClient{}
+	cli.setTools("alpha")
+
+	proxy, err := coretool.NewProxy(ctx, "test", cli)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotNil(t, proxy.Methods().Lookup("alpha"))
+	assert.Nil(t, proxy.Methods().Lookup("beta"))
+
+	changed := make(chan struct{}, 1)
+	proxy.OnChange(func(ctx context.Context) { changed <- struct{}{} })
+
+	cli.setTools("beta")
+	proxy.OnNotification(ctx, &jsonrpc.Notification{Method: coretool.MethodNotificationToolsListChanged})
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("proxy was not refreshed")
+	}
+
+	assert.Nil(t, proxy.Methods().Lookup("alpha"))
+	assert.NotNil(t, proxy.Methods().Lookup("beta"))
+
+	_, err = proxy.Method("alpha")
+	assert.Error(t, err)
+	exec, err := proxy.Method("beta")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var response string
+	assert.NoError(t, exec(ctx, map[string]interface{}{"message": "hi"}, &response))
+	assert.EqualValues(t, "beta", response)
+}
+
+// gatedClient holds tools/list calls, after taking the tool list, until their
+// gate is opened.
+type gatedClient struct {
+	*fakeClient
+	gates chan chan struct{}
+	lists atomic.Int32
+}
+
+func (g *gatedClient) ListTools(ctx context.Context, cursor *string, options ...mcpclient.RequestOption) (*mcpschema.ListToolsResult, error) {
+	result, err := g.fakeClient.ListTools(ctx, cursor, options...)
+	g.lists.Add(1)
+	select {
+	case gate := <-g.gates:
+		select {
+		case <-gate:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	default:
+	}
+	return result, err
+}
+
+func TestProxy_RefreshCoalesced(t *testing.T) {
+	ctx := context.Background()
+	cli := &gatedClient{fakeClient: &fakeClient{}, gates: make(chan chan struct{}, 2)}
+	cli.setTools("alpha")
+	proxy, err := coretool.NewProxy(ctx, "test", cli)
+	if !assert.NoError(t, err) {
+		return
+	}
+	changed := make(chan struct{}, 10)
+	proxy.OnChange(func(ctx context.Context) { changed <- struct{}{} })
+
+	first, second := make(chan struct{}), make(chan struct{})
+	cli.gates <- first
+	cli.gates <- second
+	notification := &jsonrpc.Notification{Method: coretool.MethodNotificationToolsListChanged}
+	for i := 0; i < 10; i++ {
+		proxy.OnNotification(ctx, notification)
+	}
+	assert.Eventually(t, func() bool { return cli.lists.Load() == 2 }, 5*time.Second, 5*time.Millisecond, "one refresh in flight")
+	cli.setTools("beta")
+	for i := 0; i < 10; i++ {
+		proxy.OnNotification(ctx, notification)
+	}
+	close(first)
+	<-changed
+	close(second)
+	<-changed
+	time.Sleep(50 * time.Millisecond)
+	assert.EqualValues(t, 3, cli.lists.Load(), "burst coalesced into one more refresh")
+	assert.Len(t, changed, 0)
+	assert.NotNil(t, proxy.Methods().Lookup("beta"))
+
+	proxy.Close()
+	cli.setTools("gamma")
+	proxy.OnNotification(ctx, notification)
+	time.Sleep(50 * time.Millisecond)
+	assert.EqualValues(t, 3, cli.lists.Load(), "no refresh once closed")
+}
+
+func TestProxy_RefreshOrdered(t *testing.T) {
+	ctx := context.Background()
+	cli := &gatedClient{fakeClient: &fakeClient{}, gates: make(chan chan struct{}, 1)}
+	cli.setTools("alpha")
+	proxy, err := coretool.NewProxy(ctx, "test", cli)
+	if !assert.NoError(t, err) {
+		return
+	}
+	gate := make(chan struct{})
+	cli.gates <- gate
+	cli.setTools("old")
+	done := make(chan error, 1)
+	go func() { done <- proxy.Refresh(ctx) }()
+	assert.Eventually(t, func() bool { return cli.lists.Load() == 2 }, 5*time.Second, 5*time.Millisecond)
+
+	cli.setTools("new")
+	assert.NoError(t, proxy.Refresh(ctx))
+	close(gate)
+	assert.NoError(t, <-done)
+	assert.NotNil(t, proxy.Methods().Lookup("new"), "older tool list listed earlier is not applied")
+	assert.Nil(t, proxy.Methods().Lookup("old"))
+}
+
+func TestProxy_IgnoresOtherNotifications(t *testing.T) {
+	ctx := context.Background()
+	cli := &fakeClient{}
+	cli.setTools("alpha")
+
+	proxy, err := coretool.NewProxy(ctx, "test", cli)
+	if !assert.NoError(t, err) {
+		return
+	}
+	proxy.OnChange(func(ctx context.Context) { t.Errorf("unexpected refresh") })
+	cli.setTools("beta")
+	proxy.OnNotification(ctx, &jsonrpc.Notification{Method: mcpschema.MethodNotificationMessage})
+	time.Sleep(50 * time.Millisecond)
+	assert.NotNil(t, proxy.Methods().Lookup("alpha"))
+}
+
+func TestProxy_Overrides(t *testing.T) {
+	ctx := context.Background()
+	cli := &fakeClient{}
+	cli.setTools("search_issues", "delete_repo", "list")
+
+	proxy, err := coretool.NewProxy(ctx, "github", cli,
+		coretool.WithOverrides(map[string]*coretool.Override{
+			"search_issues": {Name: "issues", Description: "Search issues by query"},
+			"delete_repo":   {Hidden: true},
+		}),
+		coretool.WithPrefix("gh_"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var names []string
+	for _, sig := range proxy.Methods() {
+		names = append(names, sig.Name)
+	}
+	assert.EqualValues(t, []string{"gh_issues", "gh_list"}, names)
+	assert.EqualValues(t, "Search issues by query", proxy.Methods().Lookup("gh_issues").Description)
+
+	_, err = proxy.Method("gh_delete_repo")
+	assert.Error(t, err)
+	exec, err := proxy.Method("gh_issues")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var response string
+	assert.NoError(t, exec(ctx, map[string]interface{}{}, &response))
+	assert.EqualValues(t, "search_issues", response, "calls the upstream tool name")
+}
+
+func TestProxy_OverrideCollision(t *testing.T) {
+	ctx := context.Background()
+	cli := &fakeClient{}
+	cli.setTools("search_issues", "issues")
+	overrides := coretool.WithOverrides(map[string]*coretool.Override{"search_issues": {Name: "issues"}})
+
+	_, err := coretool.NewProxy(ctx, "github", cli, overrides)
+	assert.EqualError(t, err, `tools "search_issues" and "issues" are both exposed as "issues"`)
+
+	cli.setTools("search_issues", "list")
+	proxy, err := coretool.NewProxy(ctx, "github", cli, overrides)
+	if !assert.NoError(t, err) {
+		return
+	}
+	cli.setTools("search_issues", "list", "issues")
+	assert.Error(t, proxy.Refresh(ctx), "refresh introduces a collision")
+	var names []string
+	for _, sig := range proxy.Methods() {
+		names = append(names, sig.Name)
+	}
+	assert.EqualValues(t, []string{"issues", "list"}, names, "previous tools kept")
+
+	_, err = coretool.NewProxy(ctx, "github", nil, overrides, coretool.WithManifest(&coretool.Manifest{Tools: cli.tools}))
+	assert.Error(t, err, "cached manifest with a collision")
+}
+
+func TestProxy_Presets(t *testing.T) {
+	ctx := context.Background()
+	cli := &fakeClient{}
+	cli.setTools("query")
+	cli.tools[0].InputSchema.Properties = map[string]map[string]interface{}{
+		"sql":        {"type": "string"},
+		"tenantId":   {"type": "string"},
+		"apiVersion": {"type": "string"},
+	}
+	cli.tools[0].InputSchema.Required = []string{"sql", "tenantId"}
+
+	proxy, err := coretool.NewProxy(ctx, "analytics", cli, coretool.WithOverrides(map[string]*coretool.Override{
+		"query": {Presets: map[string]*coretool.Preset{
+			"tenantId":   {Value: "acme", Locked: true},
+			"apiVersion": {Value: "2024-01"},
+		}},
+	}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	input := proxy.Methods().Lookup("query").Input
+	_, hasTenant := input.FieldByName("TenantId")
+	assert.False(t, hasTenant, "locked preset removed from schema")
+	_, hasVersion := input.FieldByName("ApiVersion")
+	assert.True(t, hasVersion, "default preset stays in schema")
+
+	exec, err := proxy.Method("query")
+	if !assert.NoError(t, err) {
+		return
+	}
+	testCases := []struct {
+		description string
+		args        map[string]interface{}
+		expect      map[string]interface{}
+	}{
+		{
+			description: "presets applied",
+			args:        map[string]interface{}{"sql": "select 1"},
+			expect:      map[string]interface{}{"sql": "select 1", "tenantId": "acme", "apiVersion": "2024-01"},
+		},
+		{
+			description: "default overridden, locked kept",
+			args:        map[string]interface{}{"sql": "select 1", "tenantId": "other", "apiVersion": "2025-01"},
+			expect:      map[string]interface{}{"sql": "select 1", "tenantId": "acme", "apiVersion": "2025-01"},
+		},
+	}
+	for _, tc := range testCases {
+		var response string
+		assert.NoError(t, exec(ctx, tc.args, &response), tc.description)
+		assert.EqualValues(t, tc.expect, cli.args, tc.description)
+	}
+
+	// an explicit empty value survives encoding into the input struct
+	args := proxy.WithPresets("query", map[string]interface{}{"sql": "select 1", "apiVersion": ""})
+	assert.EqualValues(t, map[string]interface{}{"sql": "select 1", "tenantId": "acme", "apiVersion": ""}, args)
+	data, err := json.Marshal(args)
+	assert.NoError(t, err)
+	encoded := reflect.New(input).Interface()
+	assert.NoError(t, json.Unmarshal(data, encoded))
+	var response string
+	assert.NoError(t, exec(ctx, encoded, &response))
+	assert.EqualValues(t, map[string]interface{}{"sql": "select 1", "tenantId": "acme", "apiVersion": ""}, cli.args)
+}
+
+func TestProxy_Retry(t *testing.T) {
+	ctx := context.Background()
+	internal := jsonrpc.NewInternalError("connection reset", nil)
+	invalid := jsonrpc.NewError(jsonrpc.InvalidParams, "bad sql", nil)
+	testCases := []struct {
+		description string
+		policy      *coretool.RetryPolicy
+		override    *coretool.RetryPolicy
+		errs        []error
+		expectCalls int
+		expectErr   string
+	}{
+		{
+			description: "no policy – single attempt",
+			errs:        []error{internal},
+			expectCalls: 1,
+			expectErr:   `[upstream internal] call tool "query": ` + internal.Error(),
+		},
+		{
+			description: "retried until success",
+			policy:      &coretool.RetryPolicy{MaxAttempts: 3, InitialBackoffMs: 1},
+			errs:        []error{internal, internal},
+			expectCalls: 3,
+		},
+		{
+			description: "attempts exhausted",
+			policy:      &coretool.RetryPolicy{MaxAttempts: 2, InitialBackoffMs: 1},
+			errs:        []error{internal, internal, internal},
+			expectCalls: 2,
+			expectErr:   `[upstream internal] call tool "query" (attempt 2/2): ` + internal.Error(),
+		},
+		{
+			description: "non retryable class",
+			policy:      &coretool.RetryPolicy{MaxAttempts: 3, InitialBackoffMs: 1},
+			errs:        []error{invalid},
+			expectCalls: 1,
+			expectErr:   `call tool "query" (attempt 1/3): ` + invalid.Error(),
+		},
+		{
+			description: "per-tool override",
+			policy:      &coretool.RetryPolicy{MaxAttempts: 3, InitialBackoffMs: 1},
+			override:    &coretool.RetryPolicy{MaxAttempts: 1},
+			errs:        []error{internal},
+			expectCalls: 1,
+			expectErr:   `[upstream internal] call tool "query": ` + internal.Error(),
+		},
+	}
+	for _, testCase := range testCases {
+		cli := &fakeClient{}
+		cli.setTools("query")
+		cli.errs = testCase.errs
+		proxy, err := coretool.NewProxy(ctx, "analytics", cli,
+			coretool.WithRetry(testCase.policy),
+			coretool.WithOverrides(map[string]*coretool.Override{"query": {Retry: testCase.override}}))
+		if !assert.NoError(t, err, testCase.description) {
+			continue
+		}
+		exec, err := proxy.Method("query")
+		if !assert.NoError(t, err, testCase.description) {
+			continue
+		}
+		var response string
+		err = exec(ctx, map[string]interface{}{}, &response)
+		assert.EqualValues(t, testCase.expectCalls, cli.calls, testCase.description)
+		if testCase.expectErr == "" {
+			assert.NoError(t, err, testCase.description)
+			continue
+		}
+		assert.EqualError(t, err, testCase.expectErr, testCase.description)
+	}
+}
+
+func TestProxy_CircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	cli := &fakeClient{}
+	cli.setTools("query")
+	failure := jsonrpc.NewInternalError("connection refused", nil)
+	cli.errs = []error{failure, failure}
+	proxy, err := coretool.NewProxy(ctx, "analytics", cli,
+		coretool.WithCircuitBreaker(&coretool.BreakerPolicy{FailureThreshold: 2, OpenSec: 60}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	exec, err := proxy.Method("query")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var response string
+	err = exec(ctx, map[string]interface{}{}, &response)
+	assert.EqualError(t, err, `[upstream internal] call tool "query" (circuit closed, 1/2 failures): `+failure.Error())
+	err = exec(ctx, map[string]interface{}{}, &response)
+	assert.Error(t, err)
+
+	state, ok := proxy.CircuitState()
+	assert.True(t, ok)
+	assert.EqualValues(t, coretool.CircuitOpen, state.State)
+
+	err = exec(ctx, map[string]interface{}{}, &response)
+	assert.ErrorIs(t, err, coretool.ErrCircuitOpen)
+	assert.Contains(t, err.Error(), "after 2 consecutive failures")
+	var callErr *coretool.CallError
+	if assert.ErrorAs(t, err, &callErr) {
+		assert.EqualValues(t, coretool.ErrorClassCircuitOpen, callErr.Class)
+		assert.True(t, callErr.Retryable)
+	}
+	assert.EqualValues(t, 2, cli.calls, "open circuit fails fast")
+}
+
+func TestProxy_Manifest(t *testing.T) {
+	ctx := context.Background()
+	cli := &fakeClient{}
+	cli.setTools("alpha", "beta")
+	manifest := &coretool.Manifest{Tools: []mcpschema.Tool{{Name: "alpha"}}}
+
+	proxy, err := coretool.NewProxy(ctx, "test", cli, coretool.WithManifest(manifest))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotNil(t, proxy.Methods().Lookup("alpha"))
+	assert.Nil(t, proxy.Methods().Lookup("beta"), "built from the manifest, not the server")
+
+	assert.NoError(t, proxy.Refresh(ctx))
+	assert.NotNil(t, proxy.Methods().Lookup("beta"))
+}
+
+// rawClient lists tools as raw tools/list results.
+type rawClient struct {
+	*fakeClient
+	result string
+}
+
+func (r *rawClient) ListToolsJSON(_ context.Context, _ *string) (json.RawMessage, error) {
+	return json.RawMessage(r.result), nil
+}
+
+// TestProxy_RootDefs verifies that references to a root "$defs" of an input
+// schema are resolved, both for listed tools and for tools loaded from a
+// manifest written by the proxy.
+func TestProxy_RootDefs(t *testing.T) {
+	ctx := context.Background()
+	cli := &rawClient{fakeClient: &fakeClient{}, result: `{"tools":[{"name":"search","inputSchema":{
+		"type":"object",
+		"$defs":{"filter":{"type":"object","properties":{"column":{"type":"string"},"value":{"type":"string"}},"required":["column"]}},
+		"properties":{"where":{"$ref":"#/$defs/filter"}},
+		"required":["where"]}}]}`}
+	// where resolves to the filter object
+	assertFilter := func(proxy *coretool.Proxy, description string) {
+		sig := proxy.Methods().Lookup("search")
+		if !assert.NotNil(t, sig, description) {
+			return
+		}
+		toolSchema, err := conversion.BuildSchema(sig)
+		if !assert.NoError(t, err, description) {
+			return
+		}
+		where := toolSchema.InputSchema.Properties["where"]
+		assert.EqualValues(t, "object", where["type"], description)
+		assert.Contains(t, where["properties"], "column", description)
+		assert.EqualValues(t, []string{"column"}, where["required"], description)
+		assert.EqualValues(t, []string{"where"}, toolSchema.InputSchema.Required, description)
+	}
+
+	proxy, err := coretool.NewProxy(ctx, "catalog", cli)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assertFilter(proxy, "listed")
+
+	data, err := json.Marshal(proxy.Manifest())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, string(data), `"$defs"`, "complete schema kept in the manifest")
+	location := filepath.Join(t.TempDir(), "catalog.json")
+	if !assert.NoError(t, os.WriteFile(location, data, 0644)) {
+		return
+	}
+	manifest, err := coretool.LoadManifest(ctx, location)
+	if !assert.NoError(t, err) {
+		return
+	}
+	proxy, err = coretool.NewProxy(ctx, "catalog", cli.fakeClient, coretool.WithManifest(manifest))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assertFilter(proxy, "from manifest")
+}
+
+func TestProxy_RefreshUnchanged(t *testing.T) {
+	ctx := context.Background()
+	cli := &fakeClient{}
+	cli.setTools("alpha")
+	proxy, err := coretool.NewProxy(ctx, "test", cli)
+	if !assert.NoError(t, err) {
+		return
+	}
+	etag := proxy.Manifest().ETag
+	assert.NotEmpty(t, etag)
+	changes := 0
+	proxy.OnChange(func(ctx context.Context) { changes++ })
+
+	assert.NoError(t, proxy.Refresh(ctx))
+	assert.EqualValues(t, 1, changes, "listeners notified after every refresh")
+	assert.EqualValues(t, etag, proxy.Manifest().ETag, "same tool set – same ETag")
+	cli.setTools("alpha", "beta")
+	assert.NoError(t, proxy.Refresh(ctx))
+	assert.EqualValues(t, 2, changes)
+	assert.NotEqual(t, etag, proxy.Manifest().ETag)
+}
+
+func TestProxy_Credentials(t *testing.T) {
+	ctx := context.Background()
+	cli := &fakeClient{}
+	cli.setTools("query")
+	proxy, err := coretool.NewProxy(ctx, "analytics", cli, coretool.WithCredentials(func(ctx context.Context) (string, error) {
+		if caller, ok := mcontext.Caller(ctx); ok {
+			return "scoped-" + caller.Token, nil
+		}
+		return "", nil
+	}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	exec, err := proxy.Method("query")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var response string
+	assert.NoError(t, exec(mcontext.WithAuthToken(ctx, "service"), map[string]interface{}{}, &response))
+	assert.EqualValues(t, "service", cli.token, "no caller – explicit token kept")
+	assert.NoError(t, exec(mcontext.WithCaller(ctx, &mcontext.Principal{Token: "alice"}), map[string]interface{}{}, &response))
+	assert.EqualValues(t, "scoped-alice", cli.token)
+}
+
+func TestProxy_Annotations(t *testing.T) {
+	ctx := context.Background()
+	cli := &fakeClient{}
+	cli.setTools("query", "drop")
+	cli.tools[0].Annotations = &mcpschema.ToolAnnotations{ReadOnlyHint: conv.Pointer(true), Title: conv.Pointer("Run query")}
+	proxy, err := coretool.NewProxy(ctx, "analytics", cli)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var annotator coretool.Annotator = proxy
+	assert.EqualValues(t, &coretool.Annotations{Title: "Run query", ReadOnly: conv.Pointer(true)}, annotator.Annotations("query"))
+	assert.Nil(t, annotator.Annotations("drop"), "upstream tool without annotations")
+
+	merged := annotator.Annotations("query").Merge(&coretool.Annotations{ReadOnly: conv.Pointer(false), Destructive: conv.Pointer(true)})
+	assert.EqualValues(t, &mcpschema.ToolAnnotations{Title: conv.Pointer("Run query"), ReadOnlyHint: conv.Pointer(false), DestructiveHint: conv.Pointer(true)}, merged.Schema())
+	assert.Nil(t, (&coretool.Annotations{}).Schema())
+}
@@ -0,0 +1,109 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/viant/fluxor-mcp/mcp/config"
+	"github.com/viant/jsonrpc"
+)
+
+// Error classes used by RetryPolicy.On.
+const (
+	// ErrorClassTransport covers connection level failures that did not
+	// produce a JSON-RPC response.
+	ErrorClassTransport = "transport"
+	// ErrorClassInternal covers JSON-RPC internal errors (-32603); the MCP
+	// client also reports transport failures this way.
+	ErrorClassInternal = "internal"
+	// ErrorClassTimeout covers calls that exceeded their deadline.
+	ErrorClassTimeout = "timeout"
+	// ErrorClassTool covers results flagged with isError by the tool itself.
+	ErrorClassTool = "tool"
+)
+
+// Default retry settings.
+const (
+	DefaultRetryInitialBackoff = 200 * time.Millisecond
+	DefaultRetryMaxBackoff     = 5 * time.Second
+)
+
+// RetryPolicy controls how many times a failed upstream call is attempted
+// (see config.RetryPolicy).
+type RetryPolicy = config.RetryPolicy
+
+// retryAttempts returns the maximum number of attempts, at least one.
+func retryAttempts(r *RetryPolicy) int {
+	if r == nil || r.MaxAttempts < 1 {
+		return 1
+	}
+	return r.MaxAttempts
+}
+
+// retryOn reports whether errors of class are retried.
+func retryOn(r *RetryPolicy, class string) bool {
+	if r == nil || class == "" {
+		return false
+	}
+	if len(r.On) == 0 {
+		return class == ErrorClassTransport || class == ErrorClassInternal
+	}
+	for _, candidate := range r.On {
+		if candidate == class {
+			return true
+		}
+	}
+	return false
+}
+
+// retryBackoff returns the delay before the given (1-based) retry.
+func retryBackoff(r *RetryPolicy, retry int) time.Duration {
+	initial, max := DefaultRetryInitialBackoff, DefaultRetryMaxBackoff
+	if r.InitialBackoffMs > 0 {
+		initial = time.Duration(r.InitialBackoffMs) * time.Millisecond
+	}
+	if r.MaxBackoffMs > 0 {
+		max = time.Duration(r.MaxBackoffMs) * time.Millisecond
+	}
+	delay := initial
+	for i := 1; i < retry && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// errorClass classifies an upstream call error; it returns an empty class for
+// errors that are neither retried nor counted as upstream failures.
+func errorClass(err error) string {
+	var rpcErr *jsonrpc.Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.Is(err, context.Canceled), errors.Is(err, ErrCircuitOpen):
+		return ""
+	case errors.As(err, &rpcErr):
+		if rpcErr.Code == jsonrpc.InternalError {
+			return ErrorClassInternal
+		}
+		return ""
+	}
+	return ErrorClassTransport
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
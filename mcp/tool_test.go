@@ -2,9 +2,21 @@ package mcp
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/viant/fluxor-mcp/mcp/config"
+	"github.com/viant/fluxor-mcp/mcp/tool"
+	"github.com/viant/fluxor-mcp/mcp/tool/conversion"
+	"github.com/viant/fluxor/model/types"
+	"github.com/viant/fluxor/runtime/execution"
+	"github.com/viant/mcp"
+	mcpschema "github.com/viant/mcp-protocol/schema"
 )
 
 // TestServiceTools ensures that the service exposes a tool entry for every
@@ -45,3 +57,302 @@ func TestServiceTools(t *testing.T) {
 		}
 	}
 }
+
+// TestWaitExecution verifies that a cancelled request stops waiting for the
+// scheduled execution instead of blocking until its timeout, and that a wait
+// running into the timeout is reported as such.
+func TestWaitExecution(t *testing.T) {
+	testCases := []struct {
+		description string
+		delay       time.Duration
+		cancel      bool
+		timedOut    bool
+		expectErr   error
+	}{
+		{description: "completed", delay: 0},
+		{description: "cancelled", delay: time.Minute, cancel: true, expectErr: context.Canceled},
+		{description: "timed out", timedOut: true, expectErr: context.DeadlineExceeded},
+	}
+
+	for _, tc := range testCases {
+		ctx, cancel := context.WithCancel(context.Background())
+		if tc.cancel {
+			cancel()
+		}
+		waitFn := func(timeout time.Duration) (*execution.Execution, error) {
+			if tc.timedOut {
+				time.Sleep(timeout)
+				return nil, errors.New("timeout") // untyped, as returned by the runtime
+			}
+			time.Sleep(tc.delay)
+			return &execution.Execution{ID: "1"}, nil
+		}
+		timeout := time.Minute
+		if tc.timedOut {
+			timeout = 10 * time.Millisecond
+		}
+		started := time.Now()
+		anExec, err := waitExecution(ctx, waitFn, timeout)
+		cancel()
+		assert.Less(t, time.Since(started), time.Second, tc.description)
+		if tc.expectErr != nil {
+			assert.True(t, errors.Is(err, tc.expectErr), tc.description)
+			assert.Nil(t, anExec, tc.description)
+			continue
+		}
+		assert.NoError(t, err, tc.description)
+		assert.EqualValues(t, "1", anExec.ID, tc.description)
+	}
+}
+
+// TestServiceToolResult verifies that object outputs are returned as
+// structuredContent with a text mirror and optionally validated.
+func TestServiceToolResult(t *testing.T) {
+	type output struct {
+		Status string `json:"status"`
+		Count  int    `json:"count"`
+	}
+	outputSchema := &mcpschema.ToolOutputSchema{
+		Type:       "object",
+		Properties: map[string]map[string]interface{}{"status": {"type": "string"}, "count": {"type": "integer"}},
+		Required:   []string{"status", "count"},
+	}
+	testCases := []struct {
+		description      string
+		output           interface{}
+		validate         bool
+		expectStructured map[string]interface{}
+		expectText       string
+		expectError      bool
+	}{
+		{
+			description:      "struct output",
+			output:           &output{Status: "ok", Count: 2},
+			expectStructured: map[string]interface{}{"status": "ok", "count": float64(2)},
+			expectText:       `{"status":"ok","count":2}`,
+		},
+		{
+			description: "text output",
+			output:      "done",
+			expectText:  "done",
+		},
+		{
+			description: "array output",
+			output:      []int{1, 2},
+			expectText:  "[1,2]",
+		},
+		{
+			description: "invalid output",
+			output:      map[string]interface{}{"status": 1},
+			validate:    true,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		svc := &Service{config: &config.Config{Tools: &config.Tools{ValidateOutput: tc.validate}}}
+		res := svc.toolResult(tc.output, outputSchema)
+		if tc.expectError {
+			assert.True(t, *res.IsError, tc.description)
+			assert.Contains(t, res.Content[0].Text, "count: is required", tc.description)
+			continue
+		}
+		assert.Nil(t, res.IsError, tc.description)
+		assert.EqualValues(t, tc.expectStructured, res.StructuredContent, tc.description)
+		assert.EqualValues(t, tc.expectText, res.Content[0].Text, tc.description)
+	}
+}
+
+// TestServiceTools_Naming verifies that sanitized tool names are served and
+// both served and canonical names resolve.
+func TestServiceTools_Naming(t *testing.T) {
+	ctx := context.Background()
+	svc, err := New(ctx, WithConfig(&config.Config{Tools: &config.Tools{Naming: &config.Naming{MaxLength: 16}}}))
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	tools := svc.Tools()
+	if len(tools) == 0 {
+		t.Skip("no tools registered – nothing left to test")
+	}
+	mapping := svc.ToolNames()
+	for _, te := range tools {
+		served := te.Metadata.Name
+		assert.LessOrEqual(t, len(served), 16, served)
+		canonical, ok := mapping[served]
+		if !assert.True(t, ok, served) {
+			continue
+		}
+		for _, name := range []string{served, canonical} {
+			entry, err := svc.LookupTool(name)
+			if assert.NoError(t, err, name) {
+				assert.EqualValues(t, served, entry.Metadata.Name)
+			}
+		}
+	}
+}
+
+// TestServiceTools_NamingIndex verifies that served names resolve before the
+// tool list is built and that the index is rebuilt on refresh.
+func TestServiceTools_NamingIndex(t *testing.T) {
+	ctx := context.Background()
+	svc, err := New(ctx, WithConfig(&config.Config{Tools: &config.Tools{Naming: &config.Naming{MaxLength: 16}}}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	mapping := svc.ToolNames()
+	if !assert.NotEmpty(t, mapping) {
+		return
+	}
+	svc.naming = tool.NewNaming(16)
+	for served, canonical := range mapping {
+		if served == canonical {
+			continue
+		}
+		assert.EqualValues(t, served, svc.canonicalToolName(served), "not indexed before refresh")
+		svc.RefreshTools(ctx)
+		assert.EqualValues(t, canonical, svc.canonicalToolName(served), served)
+		break
+	}
+}
+
+// TestService_InputValidation verifies that arguments of a proxied tool are
+// checked against the input schema advertised upstream – including root
+// "$defs" and composition keywords – before execution.
+func TestService_InputValidation(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	manifest := `{"tools":[{"name":"query","inputSchema":{"type":"object",
+		"$defs":{"id":{"type":"integer","minimum":1}},
+		"properties":{
+			"sql":{"type":"string"},
+			"limit":{"type":"integer","minimum":1},
+			"owner":{"$ref":"#/$defs/id"},
+			"target":{"anyOf":[{"$ref":"#/$defs/id"},{"type":"string","pattern":"^[a-z]+$"}]},
+			"format":{"oneOf":[{"type":"string","enum":["csv","json"]},{"type":"object","properties":{"delimiter":{"type":"string"}},"required":["delimiter"]}]}},
+		"required":["sql"]}}],"createdAt":"2020-01-01T00:00:00Z"}`
+	if !assert.NoError(t, os.WriteFile(filepath.Join(cacheDir, "offline.json"), []byte(manifest), 0644)) {
+		return
+	}
+	options := &mcp.ClientOptions{Name: "offline", Version: "v1"}
+	options.Transport.Type = "stdio"
+	options.Transport.Command = filepath.Join(cacheDir, "missing-server")
+
+	svc, err := New(ctx, WithConfig(&config.Config{
+		MCP:       &config.Group[*config.MCPClient]{Items: []*config.MCPClient{{ClientOptions: options}}},
+		ToolCache: &config.ToolCache{URL: cacheDir},
+		Bootstrap: &config.Bootstrap{TimeoutSec: 1},
+	}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer svc.Shutdown(ctx)
+
+	testCases := []struct {
+		description string
+		args        map[string]interface{}
+		expect      []conversion.FieldError
+	}{
+		{
+			description: "property constraints",
+			args:        map[string]interface{}{"limit": 0},
+			expect: []conversion.FieldError{
+				{Field: "limit", Message: "must be >= 1"},
+				{Field: "sql", Message: "is required"},
+			},
+		},
+		{
+			description: "root $defs reference",
+			args:        map[string]interface{}{"sql": "SELECT 1", "owner": 0},
+			expect: []conversion.FieldError{
+				{Field: "owner", Message: "must be >= 1"},
+			},
+		},
+		{
+			description: "anyOf and oneOf",
+			args:        map[string]interface{}{"sql": "SELECT 1", "target": "Orders", "format": map[string]interface{}{}},
+			expect: []conversion.FieldError{
+				{Field: "format", Message: "must match exactly one oneOf schema, matched 0"},
+				{Field: "target", Message: "must match at least one anyOf schema"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		_, err = svc.ExecuteTool(ctx, "offline-query", testCase.args, time.Second)
+		var execErr *ExecutionError
+		if assert.ErrorAs(t, err, &execErr, testCase.description) {
+			assert.EqualValues(t, ErrorCodeInvalidArguments, execErr.Code, testCase.description)
+			assert.EqualValues(t, testCase.expect, execErr.Errors, testCase.description)
+		}
+	}
+}
+
+// nativeInput is documented like the inputs of built-in actions: non-pointer
+// fields without omitempty are required unless tagged otherwise.
+type nativeInput struct {
+	Path  string  `json:"path"`
+	Mode  string  `json:"mode,omitempty" required:"true"`
+	Limit int     `json:"limit" minimum:"1" required:"false"`
+	Note  *string `json:"note"`
+}
+
+// nativeService is a native action service registered like a built-in one.
+type nativeService struct{}
+
+func (s *nativeService) Name() string { return "native" }
+
+func (s *nativeService) Methods() types.Signatures {
+	return types.Signatures{{Name: "run", Input: reflect.TypeOf(&nativeInput{}), Output: reflect.TypeOf(&struct{}{})}}
+}
+
+func (s *nativeService) Method(name string) (types.Executable, error) {
+	return func(context.Context, interface{}, interface{}) error { return nil }, nil
+}
+
+// TestService_NativeInputValidation verifies that arguments of native actions
+// are validated against their advertised schema: untagged non-pointer fields
+// are required and required tags add or remove fields.
+func TestService_NativeInputValidation(t *testing.T) {
+	ctx := context.Background()
+	svc, err := New(ctx, WithConfig(&config.Config{}), WithExtensions(&nativeService{}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	svc.runtime = &fakeRuntime{run: func(ctx context.Context) (interface{}, error) { return "done", nil }}
+	entry, err := svc.LookupTool("native-run")
+	if assert.NoError(t, err) {
+		assert.EqualValues(t, []string{"path", "mode"}, entry.Metadata.InputSchema.Required, "advertised required fields")
+	}
+
+	testCases := []struct {
+		description string
+		args        map[string]interface{}
+		expect      []conversion.FieldError
+	}{
+		{
+			description: "optional fields omitted",
+			args:        map[string]interface{}{"path": "/tmp", "mode": "fast"},
+		},
+		{
+			description: "required fields omitted",
+			args:        map[string]interface{}{"limit": 0},
+			expect: []conversion.FieldError{
+				{Field: "limit", Message: "must be >= 1"},
+				{Field: "mode", Message: "is required"},
+				{Field: "path", Message: "is required"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		_, err = svc.ExecuteTool(ctx, "native-run", testCase.args, time.Second)
+		if testCase.expect == nil {
+			assert.NoError(t, err, testCase.description)
+			continue
+		}
+		var execErr *ExecutionError
+		if assert.ErrorAs(t, err, &execErr, testCase.description) {
+			assert.EqualValues(t, ErrorCodeInvalidArguments, execErr.Code, testCase.description)
+			assert.EqualValues(t, testCase.expect, execErr.Errors, testCase.description)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package upstream
+
+import (
+	"net/http"
+
+	authtransport "github.com/viant/mcp/client/auth/transport"
+)
+
+// httpClient returns the client of the HTTP transports of servers configured
+// without auth. It sends the caller token a request carries, as the
+// authorizing transport of mcp.NewClient does.
+func httpClient() *http.Client {
+	return &http.Client{Transport: &tokenRoundTripper{base: http.DefaultTransport}}
+}
+
+// tokenRoundTripper sets the Authorization header from the token stored in
+// the request context under authtransport.ContextAuthTokenKey.
+type tokenRoundTripper struct {
+	base http.RoundTripper
+}
+
+func (t *tokenRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	token, _ := request.Context().Value(authtransport.ContextAuthTokenKey).(string)
+	if token == "" {
+		return t.base.RoundTrip(request)
+	}
+	request = request.Clone(request.Context())
+	request.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(request)
+}
@@ -0,0 +1,53 @@
+package upstream
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/viant/mcp"
+	mcpschema "github.com/viant/mcp-protocol/schema"
+	protoserver "github.com/viant/mcp-protocol/server"
+	mcpclient "github.com/viant/mcp/client"
+)
+
+// TestDial_HTTPToken verifies that the caller token reaches an HTTP upstream
+// configured without auth as an Authorization header. Only sse is covered:
+// the viant/jsonrpc streaming client posts messages with a client created
+// before its WithHTTPClient option applies.
+func TestDial_HTTPToken(t *testing.T) {
+	ctx := context.Background()
+	server, err := mcp.NewServer(protoserver.WithDefaultHandler(ctx), &mcp.ServerOptions{Name: "helper", Version: "v1"})
+	require.NoError(t, err)
+	handler := server.HTTP(ctx, "").Handler
+	var mu sync.Mutex
+	var headers []string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if value := request.Header.Get("Authorization"); value != "" {
+			mu.Lock()
+			headers = append(headers, value)
+			mu.Unlock()
+		}
+		handler.ServeHTTP(writer, request)
+	}))
+	defer httpServer.Close()
+
+	options := &mcp.ClientOptions{Name: "helper", Version: "v1"}
+	options.Transport.Type = "sse"
+	options.Transport.URL = httpServer.URL + "/sse"
+	conn, err := Dial(ctx, nil, options)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Ping(ctx, &mcpschema.PingRequestParams{})
+	require.NoError(t, err)
+	_, err = conn.Ping(ctx, &mcpschema.PingRequestParams{}, mcpclient.WithAuthToken("secret"))
+	require.NoError(t, err)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.EqualValues(t, []string{"Bearer secret"}, headers)
+}
@@ -0,0 +1,548 @@
+package upstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/viant/jsonrpc"
+	mcpschema "github.com/viant/mcp-protocol/schema"
+	mcpclient "github.com/viant/mcp/client"
+)
+
+// State describes the health of an upstream connection.
+type State string
+
+const (
+	StateIdle         State = "idle"
+	StateConnected    State = "connected"
+	StateReconnecting State = "reconnecting"
+	StateClosed       State = "closed"
+)
+
+// ErrClosed is returned by calls on a closed client.
+var ErrClosed = errors.New("upstream closed")
+
+// Default health check settings.
+const (
+	DefaultPingInterval   = 30 * time.Second
+	DefaultPingTimeout    = 5 * time.Second
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = time.Minute
+)
+
+// Status is a snapshot of an upstream connection health.
+type Status struct {
+	Name        string    `json:"name"`
+	State       State     `json:"state"`
+	LastPing    time.Time `json:"lastPing,omitempty"`
+	LastError   string    `json:"lastError,omitempty"`
+	Failures    int       `json:"failures,omitempty"`
+	Reconnects  int       `json:"reconnects,omitempty"`
+	NextAttempt time.Time `json:"nextAttempt,omitempty"`
+}
+
+// Options controls health checking and reconnect backoff; zero values use
+// the defaults.
+type Options struct {
+	PingInterval   time.Duration
+	PingTimeout    time.Duration
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Lazy defers dialing until the first call.
+	Lazy bool
+}
+
+func (o *Options) init() {
+	if o.PingInterval <= 0 {
+		o.PingInterval = DefaultPingInterval
+	}
+	if o.PingTimeout <= 0 {
+		o.PingTimeout = DefaultPingTimeout
+	}
+	if o.InitialBackoff <= 0 {
+		o.InitialBackoff = DefaultInitialBackoff
+	}
+	if o.MaxBackoff < o.InitialBackoff {
+		o.MaxBackoff = DefaultMaxBackoff
+		if o.MaxBackoff < o.InitialBackoff {
+			o.MaxBackoff = o.InitialBackoff
+		}
+	}
+}
+
+// Dialer opens and initialises a new connection to the upstream server.
+type Dialer func(ctx context.Context) (mcpclient.Interface, error)
+
+// Client is a self-healing mcpclient.Interface. Calls are delegated to the
+// current connection, which is replaced after a failed health check.
+type Client struct {
+	name        string
+	dial        Dialer
+	options     Options
+	mu          sync.RWMutex
+	current     mcpclient.Interface
+	status      Status
+	listeners   []func(ctx context.Context)
+	checkNow    chan struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+	startOnce   sync.Once
+	reconnectMu sync.Mutex
+}
+
+// New dials the upstream, unless options.Lazy is set, and returns a client;
+// call Start to begin health checks.
+func New(ctx context.Context, name string, dial Dialer, options Options) (*Client, error) {
+	options.init()
+	ret := &Client{
+		name:     name,
+		dial:     dial,
+		options:  options,
+		status:   Status{Name: name, State: StateIdle},
+		checkNow: make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	if options.Lazy {
+		return ret, nil
+	}
+	current, err := dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret.current = current
+	ret.status = Status{Name: name, State: StateConnected, LastPing: time.Now()}
+	return ret, nil
+}
+
+// Name returns the upstream name.
+func (c *Client) Name() string { return c.name }
+
+// Status returns the current health snapshot.
+func (c *Client) Status() Status {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status
+}
+
+// OnReconnect registers a listener called after every successful reconnect
+// and after the first connection of a lazy client, e.g. to re-discover tools.
+func (c *Client) OnReconnect(listener func(ctx context.Context)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners = append(c.listeners, listener)
+}
+
+// Start launches the background health check loop.
+func (c *Client) Start() {
+	c.startOnce.Do(func() { go c.run() })
+}
+
+// Close stops health checks and closes the current connection.
+func (c *Client) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.mu.Lock()
+		c.status.State = StateClosed
+		current := c.current
+		c.current = nil
+		c.mu.Unlock()
+		closeConn(current)
+	})
+}
+
+// closeConn releases a connection that supports io.Closer, e.g. a Conn.
+func closeConn(conn mcpclient.Interface) {
+	if closer, ok := conn.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
+
+func (c *Client) run() {
+	ticker := time.NewTicker(c.options.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+		case <-c.checkNow:
+		}
+		_ = c.Check(context.Background())
+	}
+}
+
+// Check pings the upstream and reconnects when the ping fails. It blocks
+// until the connection is healthy again or the client is closed.
+func (c *Client) Check(ctx context.Context) error {
+	if c.Status().State == StateIdle {
+		return nil // lazy client not used yet
+	}
+	err := c.ping(ctx)
+	if err == nil {
+		return nil
+	}
+	return c.reconnect(ctx, err)
+}
+
+func (c *Client) ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.options.PingTimeout)
+	defer cancel()
+	current, err := c.conn(ctx)
+	if err == nil {
+		_, err = current.Ping(ctx, &mcpschema.PingRequestParams{})
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		c.status.Failures++
+		c.status.LastError = err.Error()
+		return err
+	}
+	c.status.LastPing = time.Now()
+	c.status.Failures = 0
+	c.status.LastError = ""
+	return nil
+}
+
+// reconnect dials a new connection with exponential backoff, swaps it in and
+// notifies listeners.
+func (c *Client) reconnect(ctx context.Context, cause error) error {
+	c.reconnectMu.Lock()
+	defer c.reconnectMu.Unlock()
+	if c.Status().State == StateConnected && c.ping(ctx) == nil {
+		return nil // healed by a concurrent reconnect
+	}
+	c.setState(StateReconnecting, cause)
+	backoff := c.options.InitialBackoff
+	for {
+		current, err := c.dial(ctx)
+		if err == nil {
+			c.mu.Lock()
+			if c.status.State == StateClosed {
+				c.mu.Unlock()
+				closeConn(current)
+				return fmt.Errorf("upstream %v: %w", c.name, ErrClosed)
+			}
+			previous := c.current
+			if previous != nil {
+				c.status.Reconnects++
+			}
+			c.current = current
+			c.status.State = StateConnected
+			c.status.Failures = 0
+			c.status.LastError = ""
+			c.status.LastPing = time.Now()
+			c.status.NextAttempt = time.Time{}
+			listeners := append([]func(ctx context.Context){}, c.listeners...)
+			c.mu.Unlock()
+			closeConn(previous)
+			for _, listener := range listeners {
+				listener(ctx)
+			}
+			return nil
+		}
+		c.mu.Lock()
+		c.status.Failures++
+		c.status.LastError = err.Error()
+		c.status.NextAttempt = time.Now().Add(backoff)
+		c.mu.Unlock()
+		timer := time.NewTimer(backoff)
+		select {
+		case <-c.done:
+			timer.Stop()
+			return fmt.Errorf("upstream %v closed: %w", c.name, err)
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > c.options.MaxBackoff {
+			backoff = c.options.MaxBackoff
+		}
+	}
+}
+
+func (c *Client) setState(state State, cause error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status.State = state
+	if cause != nil {
+		c.status.LastError = cause.Error()
+	}
+}
+
+// conn returns the current connection, dialing it on first use of a lazy
+// client.
+func (c *Client) conn(ctx context.Context) (mcpclient.Interface, error) {
+	c.mu.RLock()
+	current, closed := c.current, c.status.State == StateClosed
+	c.mu.RUnlock()
+	if closed {
+		return nil, fmt.Errorf("upstream %v: %w", c.name, ErrClosed)
+	}
+	if current != nil {
+		return current, nil
+	}
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.current, nil
+}
+
+// Connect dials a lazy client that has not been used yet; concurrent callers
+// wait for the result. It is a no-op once connected.
+func (c *Client) Connect(ctx context.Context) error {
+	c.reconnectMu.Lock()
+	defer c.reconnectMu.Unlock()
+	c.mu.RLock()
+	connected, closed := c.current != nil, c.status.State == StateClosed
+	c.mu.RUnlock()
+	if closed {
+		return fmt.Errorf("connect upstream %v: %w", c.name, ErrClosed)
+	}
+	if connected {
+		return nil
+	}
+	current, err := c.dial(ctx)
+	c.mu.Lock()
+	if err != nil {
+		c.status.Failures++
+		c.status.LastError = err.Error()
+		c.mu.Unlock()
+		return fmt.Errorf("connect upstream %v: %w", c.name, err)
+	}
+	if c.status.State == StateClosed {
+		c.mu.Unlock()
+		closeConn(current)
+		return fmt.Errorf("connect upstream %v: %w", c.name, ErrClosed)
+	}
+	c.current = current
+	c.status.State = StateConnected
+	c.status.Failures = 0
+	c.status.LastError = ""
+	c.status.LastPing = time.Now()
+	listeners := append([]func(ctx context.Context){}, c.listeners...)
+	c.mu.Unlock()
+	for _, listener := range listeners {
+		listener(ctx)
+	}
+	return nil
+}
+
+// observe schedules an immediate health check after a call failed with a
+// connection error.
+func (c *Client) observe(err error) {
+	if !connectionError(err) {
+		return
+	}
+	select {
+	case c.checkNow <- struct{}{}:
+	default:
+	}
+}
+
+// connectionError reports whether err may be caused by a broken connection:
+// errors without a JSON-RPC response and JSON-RPC internal errors, which the
+// MCP client also uses for transport failures. Errors answered by the server,
+// e.g. invalid params or a failing tool, and cancelled calls are not.
+func connectionError(err error) bool {
+	var rpcErr *jsonrpc.Error
+	switch {
+	case err == nil, errors.Is(err, context.Canceled):
+		return false
+	case errors.As(err, &rpcErr):
+		return rpcErr.Code == jsonrpc.InternalError
+	}
+	return true
+}
+
+// Ensure Client implements mcpclient.Interface
+var _ mcpclient.Interface = (*Client)(nil)
+
+func (c *Client) Initialize(ctx context.Context, options ...mcpclient.RequestOption) (*mcpschema.InitializeResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.Initialize(ctx, options...)
+	c.observe(err)
+	return ret, err
+}
+
+func (c *Client) ListResourceTemplates(ctx context.Context, cursor *string, options ...mcpclient.RequestOption) (*mcpschema.ListResourceTemplatesResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.ListResourceTemplates(ctx, cursor, options...)
+	c.observe(err)
+	return ret, err
+}
+
+func (c *Client) ListResources(ctx context.Context, cursor *string, options ...mcpclient.RequestOption) (*mcpschema.ListResourcesResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.ListResources(ctx, cursor, options...)
+	c.observe(err)
+	return ret, err
+}
+
+func (c *Client) ListPrompts(ctx context.Context, cursor *string, options ...mcpclient.RequestOption) (*mcpschema.ListPromptsResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.ListPrompts(ctx, cursor, options...)
+	c.observe(err)
+	return ret, err
+}
+
+func (c *Client) ListTools(ctx context.Context, cursor *string, options ...mcpclient.RequestOption) (*mcpschema.ListToolsResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.ListTools(ctx, cursor, options...)
+	c.observe(err)
+	return ret, err
+}
+
+// ListToolsJSON lists tools through the current connection when it returns
+// raw tools/list results (see Conn.ListToolsJSON), errors.ErrUnsupported
+// otherwise.
+func (c *Client) ListToolsJSON(ctx context.Context, cursor *string) (json.RawMessage, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lister, ok := current.(interface {
+		ListToolsJSON(ctx context.Context, cursor *string) (json.RawMessage, error)
+	})
+	if !ok {
+		return nil, errors.ErrUnsupported
+	}
+	ret, err := lister.ListToolsJSON(ctx, cursor)
+	if !errors.Is(err, errors.ErrUnsupported) {
+		c.observe(err)
+	}
+	return ret, err
+}
+
+func (c *Client) ReadResource(ctx context.Context, params *mcpschema.ReadResourceRequestParams, options ...mcpclient.RequestOption) (*mcpschema.ReadResourceResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.ReadResource(ctx, params, options...)
+	c.observe(err)
+	return ret, err
+}
+
+func (c *Client) GetPrompt(ctx context.Context, params *mcpschema.GetPromptRequestParams, options ...mcpclient.RequestOption) (*mcpschema.GetPromptResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.GetPrompt(ctx, params, options...)
+	c.observe(err)
+	return ret, err
+}
+
+func (c *Client) CallTool(ctx context.Context, params *mcpschema.CallToolRequestParams, options ...mcpclient.RequestOption) (*mcpschema.CallToolResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.CallTool(ctx, params, options...)
+	c.observe(err)
+	return ret, err
+}
+
+func (c *Client) Complete(ctx context.Context, params *mcpschema.CompleteRequestParams, options ...mcpclient.RequestOption) (*mcpschema.CompleteResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.Complete(ctx, params, options...)
+	c.observe(err)
+	return ret, err
+}
+
+func (c *Client) Ping(ctx context.Context, params *mcpschema.PingRequestParams, options ...mcpclient.RequestOption) (*mcpschema.PingResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.Ping(ctx, params, options...)
+	c.observe(err)
+	return ret, err
+}
+
+func (c *Client) Subscribe(ctx context.Context, params *mcpschema.SubscribeRequestParams, options ...mcpclient.RequestOption) (*mcpschema.SubscribeResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.Subscribe(ctx, params, options...)
+	c.observe(err)
+	return ret, err
+}
+
+func (c *Client) Unsubscribe(ctx context.Context, params *mcpschema.UnsubscribeRequestParams, options ...mcpclient.RequestOption) (*mcpschema.UnsubscribeResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.Unsubscribe(ctx, params, options...)
+	c.observe(err)
+	return ret, err
+}
+
+func (c *Client) SetLevel(ctx context.Context, params *mcpschema.SetLevelRequestParams, options ...mcpclient.RequestOption) (*mcpschema.SetLevelResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.SetLevel(ctx, params, options...)
+	c.observe(err)
+	return ret, err
+}
+
+func (c *Client) ListRoots(ctx context.Context, params *mcpschema.ListRootsRequestParams, options ...mcpclient.RequestOption) (*mcpschema.ListRootsResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.ListRoots(ctx, params, options...)
+	c.observe(err)
+	return ret, err
+}
+
+func (c *Client) CreateMessage(ctx context.Context, params *mcpschema.CreateMessageRequestParams, options ...mcpclient.RequestOption) (*mcpschema.CreateMessageResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.CreateMessage(ctx, params, options...)
+	c.observe(err)
+	return ret, err
+}
+
+func (c *Client) Elicit(ctx context.Context, params *mcpschema.ElicitRequestParams, options ...mcpclient.RequestOption) (*mcpschema.ElicitResult, error) {
+	current, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := current.Elicit(ctx, params, options...)
+	c.observe(err)
+	return ret, err
+}
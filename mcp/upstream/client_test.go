@@ -0,0 +1,199 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/viant/jsonrpc"
+	mcpschema "github.com/viant/mcp-protocol/schema"
+	mcpclient "github.com/viant/mcp/client"
+)
+
+// pingClient is a fake connection whose Ping result is controlled by alive.
+type pingClient struct {
+	mcpclient.Interface
+	alive  *atomic.Bool
+	closed atomic.Bool
+}
+
+func (p *pingClient) Close() error {
+	p.closed.Store(true)
+	return nil
+}
+
+func (p *pingClient) Ping(_ context.Context, _ *mcpschema.PingRequestParams, _ ...mcpclient.RequestOption) (*mcpschema.PingResult, error) {
+	if !p.alive.Load() {
+		return nil, errors.New("connection reset")
+	}
+	return &mcpschema.PingResult{}, nil
+}
+
+func TestClient_Check(t *testing.T) {
+	alive := &atomic.Bool{}
+	alive.Store(true)
+	var dials, failDials int32
+	dial := func(ctx context.Context) (mcpclient.Interface, error) {
+		if atomic.AddInt32(&dials, 1) > 1 && atomic.AddInt32(&failDials, -1) >= 0 {
+			return nil, errors.New("connection refused")
+		}
+		return &pingClient{alive: alive}, nil
+	}
+	client, err := New(context.Background(), "remote", dial, Options{InitialBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond})
+	require.NoError(t, err)
+	defer client.Close()
+	reconnected := 0
+	client.OnReconnect(func(ctx context.Context) { reconnected++ })
+
+	// healthy connection – no reconnect
+	require.NoError(t, client.Check(context.Background()))
+	assert.Equal(t, StateConnected, client.Status().State)
+	assert.Equal(t, 0, reconnected)
+
+	// failed ping – two refused dials, then a fresh connection
+	alive.Store(false)
+	atomic.StoreInt32(&failDials, 2)
+	go func() {
+		time.Sleep(2 * time.Millisecond)
+		alive.Store(true)
+	}()
+	require.NoError(t, client.Check(context.Background()))
+	status := client.Status()
+	assert.Equal(t, StateConnected, status.State)
+	assert.Equal(t, 1, status.Reconnects)
+	assert.Equal(t, 0, status.Failures)
+	assert.Empty(t, status.LastError)
+	assert.Equal(t, 1, reconnected)
+	assert.EqualValues(t, 4, atomic.LoadInt32(&dials))
+
+	client.Close()
+	assert.Equal(t, StateClosed, client.Status().State)
+}
+
+func TestClient_CloseConnections(t *testing.T) {
+	alive := &atomic.Bool{}
+	alive.Store(true)
+	var conns []*pingClient
+	dial := func(ctx context.Context) (mcpclient.Interface, error) {
+		conn := &pingClient{alive: alive}
+		conns = append(conns, conn)
+		return conn, nil
+	}
+	client, err := New(context.Background(), "remote", dial, Options{InitialBackoff: time.Millisecond})
+	require.NoError(t, err)
+
+	alive.Store(false)
+	require.NoError(t, client.Check(context.Background()))
+	require.Len(t, conns, 2)
+	assert.True(t, conns[0].closed.Load(), "replaced connection closed")
+	assert.False(t, conns[1].closed.Load())
+
+	client.Close()
+	assert.True(t, conns[1].closed.Load(), "current connection closed")
+}
+
+func TestOptions_Init(t *testing.T) {
+	testCases := []struct {
+		description string
+		options     Options
+		expect      Options
+	}{
+		{
+			description: "defaults",
+			expect:      Options{PingInterval: DefaultPingInterval, PingTimeout: DefaultPingTimeout, InitialBackoff: DefaultInitialBackoff, MaxBackoff: DefaultMaxBackoff},
+		},
+		{
+			description: "max backoff not below initial",
+			options:     Options{InitialBackoff: 2 * time.Minute},
+			expect:      Options{PingInterval: DefaultPingInterval, PingTimeout: DefaultPingTimeout, InitialBackoff: 2 * time.Minute, MaxBackoff: 2 * time.Minute},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase.options.init()
+		assert.Equal(t, testCase.expect, testCase.options, testCase.description)
+	}
+}
+
+func TestClient_Lazy(t *testing.T) {
+	alive := &atomic.Bool{}
+	alive.Store(true)
+	var dials int32
+	dial := func(ctx context.Context) (mcpclient.Interface, error) {
+		atomic.AddInt32(&dials, 1)
+		return &pingClient{alive: alive}, nil
+	}
+	client, err := New(context.Background(), "remote", dial, Options{Lazy: true})
+	require.NoError(t, err)
+	defer client.Close()
+	connected := 0
+	client.OnReconnect(func(ctx context.Context) { connected++ })
+
+	require.NoError(t, client.Check(context.Background()))
+	assert.Equal(t, StateIdle, client.Status().State)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&dials), "not dialed before first use")
+
+	_, err = client.Ping(context.Background(), &mcpschema.PingRequestParams{})
+	require.NoError(t, err)
+	_, err = client.Ping(context.Background(), &mcpschema.PingRequestParams{})
+	require.NoError(t, err)
+	status := client.Status()
+	assert.Equal(t, StateConnected, status.State)
+	assert.Equal(t, 0, status.Reconnects)
+	assert.Equal(t, 1, connected)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&dials))
+}
+
+func TestClient_Closed(t *testing.T) {
+	var dials int32
+	dial := func(ctx context.Context) (mcpclient.Interface, error) {
+		atomic.AddInt32(&dials, 1)
+		return &pingClient{alive: &atomic.Bool{}}, nil
+	}
+	client, err := New(context.Background(), "remote", dial, Options{Lazy: true})
+	require.NoError(t, err)
+	client.Close()
+
+	_, err = client.Ping(context.Background(), &mcpschema.PingRequestParams{})
+	assert.ErrorIs(t, err, ErrClosed)
+	assert.ErrorIs(t, client.Connect(context.Background()), ErrClosed)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&dials), "closed client not dialed")
+}
+
+// callClient is a fake connection whose CallTool fails with err.
+type callClient struct {
+	mcpclient.Interface
+	err error
+}
+
+func (c *callClient) CallTool(context.Context, *mcpschema.CallToolRequestParams, ...mcpclient.RequestOption) (*mcpschema.CallToolResult, error) {
+	return nil, c.err
+}
+
+func TestClient_Observe(t *testing.T) {
+	testCases := []struct {
+		description string
+		err         error
+		check       bool
+	}{
+		{description: "transport error", err: errors.New("connection reset"), check: true},
+		{description: "internal error", err: jsonrpc.NewError(jsonrpc.InternalError, "broken pipe", nil), check: true},
+		{description: "deadline", err: context.DeadlineExceeded, check: true},
+		{description: "tool failure", err: jsonrpc.NewError(jsonrpc.InvalidParams, "invalid params", nil)},
+		{description: "cancelled call", err: context.Canceled},
+	}
+	for _, testCase := range testCases {
+		dial := func(ctx context.Context) (mcpclient.Interface, error) {
+			return &callClient{err: testCase.err}, nil
+		}
+		client, err := New(context.Background(), "remote", dial, Options{})
+		require.NoError(t, err, testCase.description)
+		_, err = client.CallTool(context.Background(), &mcpschema.CallToolRequestParams{Name: "query"})
+		assert.ErrorIs(t, err, testCase.err, testCase.description)
+		assert.Equal(t, testCase.check, len(client.checkNow) == 1, testCase.description)
+		client.Close()
+	}
+}
@@ -0,0 +1,199 @@
+package upstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/viant/jsonrpc"
+	"github.com/viant/jsonrpc/transport"
+	"github.com/viant/jsonrpc/transport/client/http/sse"
+	"github.com/viant/jsonrpc/transport/client/http/streaming"
+	"github.com/viant/mcp"
+	protocolclient "github.com/viant/mcp-protocol/client"
+	mcpschema "github.com/viant/mcp-protocol/schema"
+	mcpclient "github.com/viant/mcp/client"
+)
+
+// Conn is an initialised connection to an upstream server. Close releases
+// its transport: it stops a stdio server process or ends the HTTP event
+// stream.
+type Conn struct {
+	mcpclient.Interface
+	mu        sync.Mutex
+	transport transport.Transport
+	closers   []func() error
+	closed    bool
+	closeOnce sync.Once
+}
+
+// addCloser registers a release function; it runs right away when the
+// connection is already closed.
+func (c *Conn) addCloser(closer func() error) {
+	c.mu.Lock()
+	if !c.closed {
+		c.closers = append(c.closers, closer)
+		c.mu.Unlock()
+		return
+	}
+	c.mu.Unlock()
+	_ = closer()
+}
+
+// setTransport records the transport the client currently uses.
+func (c *Conn) setTransport(rpcTransport transport.Transport) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.transport = rpcTransport
+}
+
+// ListToolsJSON returns a tools/list result as sent by the server, keeping
+// the input schema keywords ListTools drops; it implements tool.RawToolLister.
+func (c *Conn) ListToolsJSON(ctx context.Context, cursor *string) (json.RawMessage, error) {
+	c.mu.Lock()
+	rpcTransport := c.transport
+	c.mu.Unlock()
+	if rpcTransport == nil {
+		return nil, errors.ErrUnsupported // connected through mcp.NewClient
+	}
+	params, err := json.Marshal(&mcpschema.ListToolsRequestParams{Cursor: cursor})
+	if err != nil {
+		return nil, err
+	}
+	response, err := rpcTransport.Send(ctx, &jsonrpc.Request{Jsonrpc: jsonrpc.Version, Method: mcpschema.MethodToolsList, Params: params})
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		return nil, response.Error
+	}
+	return response.Result, nil
+}
+
+// Close releases the transport; it is safe to call more than once.
+func (c *Conn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.closed = true
+		closers := c.closers
+		c.closers = nil
+		c.mu.Unlock()
+		for _, closer := range closers {
+			if closeErr := closer(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	})
+	return err
+}
+
+// Dial connects to the server described by options and initialises the
+// session; ctx bounds the whole handshake and a dial abandoned through ctx
+// is closed. Servers configured with auth are connected through
+// mcp.NewClient, whose transport cannot be released – Close is a no-op for
+// them and their handshake is abandoned, not stopped, when ctx is done.
+func Dial(ctx context.Context, handler protocolclient.Handler, options *mcp.ClientOptions) (*Conn, error) {
+	if options.Auth != nil && options.Transport.Type != "stdio" {
+		return dialShared(ctx, handler, options)
+	}
+	conn := &Conn{}
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	clientHandler := mcpclient.NewHandler(handler)
+	newTransport := func(ctx context.Context) (transport.Transport, error) {
+		return openTransport(conn, options, clientHandler, httpClient())
+	}
+	rpcTransport, err := newTransport(ctx)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	clientOptions := append(options.Options(nil), mcpclient.WithClientHandler(handler))
+	if options.Transport.Type != "stdio" {
+		// re-establishes an HTTP session lost after a server restart
+		clientOptions = append(clientOptions, mcpclient.WithReconnect(newTransport))
+	}
+	conn.Interface = mcpclient.New(options.Name, options.Version, rpcTransport, clientOptions...)
+	if _, err = conn.Initialize(ctx); err != nil {
+		_ = conn.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	if !stop() {
+		return nil, ctx.Err() // closed by the deadline right after the handshake
+	}
+	return conn, nil
+}
+
+// openTransport creates a transport released by conn.Close.
+func openTransport(conn *Conn, options *mcp.ClientOptions, handler transport.Handler, client *http.Client) (transport.Transport, error) {
+	switch options.Transport.Type {
+	case "stdio":
+		if options.Transport.Command == "" {
+			return nil, fmt.Errorf("command is required for stdio transport")
+		}
+		ret, err := newStdioTransport(options.Transport.Command, options.Transport.Arguments, handler)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdio transport: %w", err)
+		}
+		conn.addCloser(ret.Close)
+		conn.setTransport(ret)
+		return ret, nil
+	case "sse", "streaming":
+		if options.Transport.URL == "" {
+			return nil, fmt.Errorf("URL is required for %v transport", options.Transport.Type)
+		}
+		// the event stream lives as long as ctx
+		ctx, cancel := context.WithCancel(context.Background())
+		conn.addCloser(func() error { cancel(); return nil })
+		var ret transport.Transport
+		var err error
+		if options.Transport.Type == "sse" {
+			ret, err = sse.New(ctx, options.Transport.URL, sse.WithHandler(handler), sse.WithHttpClient(client), sse.WithMessageHttpClient(client))
+		} else {
+			ret, err = streaming.New(ctx, options.Transport.URL, streaming.WithHandler(handler), streaming.WithHTTPClient(client))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %v transport: %w", options.Transport.Type, err)
+		}
+		conn.setTransport(ret)
+		return ret, nil
+	default:
+		return nil, fmt.Errorf("no transport configured")
+	}
+}
+
+// dialShared connects through mcp.NewClient. It does not take a context, so
+// it runs in the background and an initialisation that outlives ctx is
+// abandoned.
+func dialShared(ctx context.Context, handler protocolclient.Handler, options *mcp.ClientOptions) (*Conn, error) {
+	type dialed struct {
+		client mcpclient.Interface
+		err    error
+	}
+	done := make(chan dialed, 1)
+	go func() {
+		client, err := mcp.NewClient(handler, options)
+		if err != nil {
+			done <- dialed{err: err}
+			return
+		}
+		done <- dialed{client: client}
+	}()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case ret := <-done:
+		if ret.err != nil {
+			return nil, ret.err
+		}
+		return &Conn{Interface: ret.client}, nil
+	}
+}
@@ -0,0 +1,165 @@
+package upstream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/viant/mcp"
+	mcpschema "github.com/viant/mcp-protocol/schema"
+	mcpclient "github.com/viant/mcp/client"
+)
+
+// helperEnv selects the behaviour of the test binary run as a stdio server.
+const helperEnv = "UPSTREAM_HELPER_SERVER"
+
+// TestHelperServer is not a test: run with helperEnv set it acts as a stdio
+// MCP server writing its pid to the file named by helperEnv. In "silent" mode
+// it never answers; otherwise it lists a tool whose input schema has a root
+// $defs and tools/call returns the caller token found in
+// _meta.authorization.token, or its arguments for the "args" tool.
+func TestHelperServer(t *testing.T) {
+	setting := os.Getenv(helperEnv)
+	if setting == "" {
+		return
+	}
+	mode, pidFile, _ := strings.Cut(setting, ":")
+	_ = os.WriteFile(pidFile, []byte(strconv.Itoa(os.Getpid())), 0644)
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params struct {
+				Name string `json:"name"`
+				Meta struct {
+					Authorization struct {
+						Token string `json:"token"`
+					} `json:"authorization"`
+				} `json:"_meta"`
+			} `json:"params"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &request) != nil || len(request.ID) == 0 || mode == "silent" {
+			continue
+		}
+		var result string
+		switch request.Method {
+		case mcpschema.MethodInitialize:
+			result = `{"protocolVersion":"2025-03-26","capabilities":{"tools":{}},"serverInfo":{"name":"helper","version":"v1"}}`
+		case mcpschema.MethodToolsList:
+			result = `{"tools":[{"name":"whoami","inputSchema":{"type":"object","$defs":{"id":{"type":"string"}},"properties":{"id":{"$ref":"#/$defs/id"}}}}]}`
+		case mcpschema.MethodToolsCall:
+			text := request.Params.Meta.Authorization.Token
+			if request.Params.Name == "args" {
+				text = strings.Join(flag.Args(), "\n")
+			}
+			data, _ := json.Marshal(text)
+			result = fmt.Sprintf(`{"content":[{"type":"text","text":%s}]}`, data)
+		default:
+			result = `{}`
+		}
+		fmt.Printf("{\"jsonrpc\":\"2.0\",\"id\":%s,\"result\":%s}\n", request.ID, result)
+	}
+	os.Exit(0)
+}
+
+// helperOptions configures the test binary as a stdio server in mode and
+// returns the file its pid is written to.
+func helperOptions(t *testing.T, mode string) (*mcp.ClientOptions, string) {
+	pidFile := filepath.Join(t.TempDir(), "pid")
+	t.Setenv(helperEnv, mode+":"+pidFile)
+	options := &mcp.ClientOptions{Name: "helper", Version: "v1"}
+	options.Transport.Type = "stdio"
+	options.Transport.Command = os.Args[0]
+	options.Transport.Arguments = []string{"-test.run=TestHelperServer"}
+	return options, pidFile
+}
+
+// running reports whether the process whose pid was written to pidFile is
+// alive. The server is started by a shell, so once stopped it is reaped by
+// init, which may take a while or never happen in a container: a zombie does
+// not count as running.
+func running(t *testing.T, pidFile string) bool {
+	data, err := os.ReadFile(pidFile)
+	require.NoError(t, err)
+	pid, err := strconv.Atoi(string(data))
+	require.NoError(t, err)
+	if syscall.Kill(pid, 0) != nil {
+		return false
+	}
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return true
+	}
+	_, state, _ := strings.Cut(string(stat), ") ")
+	return !strings.HasPrefix(state, "Z")
+}
+
+func TestDial_Stdio(t *testing.T) {
+	ctx := context.Background()
+	options, pidFile := helperOptions(t, "serve")
+	conn, err := Dial(ctx, nil, options)
+	require.NoError(t, err)
+
+	result, err := conn.CallTool(ctx, &mcpschema.CallToolRequestParams{Name: "whoami"}, mcpclient.WithAuthToken("secret"))
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	assert.EqualValues(t, "secret", result.Content[0].Text, "token forwarded in _meta")
+	assert.True(t, running(t, pidFile))
+
+	tools, err := conn.ListToolsJSON(ctx, nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(tools), `"$defs"`, "schema kept as sent")
+
+	require.NoError(t, conn.Close())
+	assert.Eventually(t, func() bool { return !running(t, pidFile) }, time.Second, 10*time.Millisecond, "server stopped on close")
+	_, err = conn.Ping(ctx, &mcpschema.PingRequestParams{})
+	assert.Error(t, err)
+}
+
+// TestDial_StdioArguments verifies that the command line of a stdio server is
+// interpreted by the shell, as by the viant/jsonrpc stdio client.
+func TestDial_StdioArguments(t *testing.T) {
+	ctx := context.Background()
+	options, _ := helperOptions(t, "serve")
+	t.Setenv("UPSTREAM_HELPER_ARG", "expanded")
+	options.Transport.Arguments = append(options.Transport.Arguments, "two words", "'quoted arg'", "$UPSTREAM_HELPER_ARG")
+	conn, err := Dial(ctx, nil, options)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	result, err := conn.CallTool(ctx, &mcpschema.CallToolRequestParams{Name: "args"})
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	assert.EqualValues(t, "two\nwords\nquoted arg\nexpanded", result.Content[0].Text)
+}
+
+func TestDial_Abandoned(t *testing.T) {
+	options, pidFile := helperOptions(t, "silent")
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err := Dial(ctx, nil, options)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(started), 5*time.Second)
+	assert.Eventually(t, func() bool { return !running(t, pidFile) }, time.Second, 10*time.Millisecond, "abandoned server stopped")
+}
+
+func TestDial_Unavailable(t *testing.T) {
+	options := &mcp.ClientOptions{Name: "missing", Version: "v1"}
+	options.Transport.Type = "stdio"
+	options.Transport.Command = filepath.Join(t.TempDir(), "missing-server")
+	_, err := Dial(context.Background(), nil, options)
+	assert.ErrorContains(t, err, "missing-server")
+}
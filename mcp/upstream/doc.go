@@ -0,0 +1,8 @@
+// Package upstream keeps connections to imported MCP servers healthy. A
+// Client wraps the MCP client of one upstream, pings it periodically and,
+// when the server stops responding, reconnects with exponential backoff,
+// re-initialises the session and notifies listeners so that tools can be
+// re-discovered. Dial opens a Conn whose Close stops a stdio server process
+// or ends an HTTP event stream; a Client closes connections it replaces or
+// no longer needs.
+package upstream
@@ -0,0 +1,233 @@
+package upstream
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	mcpclient "github.com/viant/mcp/client"
+)
+
+// DefaultPoolIdle is how long an unused pooled connection is kept.
+const DefaultPoolIdle = 5 * time.Minute
+
+// DefaultDialTimeout bounds dialing a pooled connection.
+const DefaultDialTimeout = 30 * time.Second
+
+// PoolOptions controls a Pool; zero values use the defaults.
+type PoolOptions struct {
+	// Idle evicts connections unused for longer than Idle.
+	Idle time.Duration
+	// MaxSize caps the number of connections (0 – unlimited); the least
+	// recently used one is evicted to make room.
+	MaxSize int
+	// DialTimeout bounds dialing a connection.
+	DialTimeout time.Duration
+	// Health controls health checks and reconnects of each connection.
+	Health Options
+	// HealthDisabled turns health checks off; a failed connection is then
+	// only replaced once evicted.
+	HealthDisabled bool
+}
+
+// Pool keeps one upstream connection per key – e.g. an inbound session or
+// user – so that stateful upstream servers do not share state between
+// callers. Every connection is a Client, health checked and reconnected like
+// a shared one, and closed when evicted.
+type Pool struct {
+	name      string
+	dial      Dialer
+	options   PoolOptions
+	mu        sync.Mutex
+	entries   map[string]*pooled
+	done      chan struct{}
+	startOnce sync.Once
+	closeOnce sync.Once
+}
+
+type pooled struct {
+	ready    chan struct{}
+	client   *Client
+	err      error
+	lastUsed time.Time
+	// refs counts callers holding the connection; it is closed only once
+	// unused.
+	refs    int
+	removed bool
+}
+
+// NewPool creates a pool dialing connections with dial; call Start to begin
+// idle eviction.
+func NewPool(name string, dial Dialer, options PoolOptions) *Pool {
+	if options.DialTimeout <= 0 {
+		options.DialTimeout = DefaultDialTimeout
+	}
+	if options.Idle <= 0 {
+		options.Idle = DefaultPoolIdle
+	}
+	return &Pool{name: name, dial: dial, options: options, entries: make(map[string]*pooled), done: make(chan struct{})}
+}
+
+// Name returns the upstream name.
+func (p *Pool) Name() string { return p.name }
+
+// Len returns the number of pooled connections.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries)
+}
+
+// Acquire returns the connection of key, dialing it on first use, and a
+// release function to call once the caller is done with it. A connection is
+// never evicted while acquired. Concurrent callers with the same key share
+// one dial, which runs detached from ctx within DialTimeout so that a caller
+// giving up does not fail it for the others; a failed dial is not pooled.
+func (p *Pool) Acquire(ctx context.Context, key string) (mcpclient.Interface, func(), error) {
+	p.mu.Lock()
+	entry, ok := p.entries[key]
+	if !ok {
+		p.makeRoom()
+		entry = &pooled{ready: make(chan struct{})}
+		p.entries[key] = entry
+	}
+	entry.refs++
+	entry.lastUsed = time.Now()
+	p.mu.Unlock()
+
+	if !ok {
+		go p.connect(context.WithoutCancel(ctx), key, entry)
+	}
+	var once sync.Once
+	release := func() { once.Do(func() { p.release(entry) }) }
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+		release()
+		return nil, nil, ctx.Err()
+	}
+	if entry.err != nil {
+		release()
+		return nil, nil, entry.err
+	}
+	return entry.client, release, nil
+}
+
+// connect dials the connection of entry, removing entry when dialing fails.
+func (p *Pool) connect(ctx context.Context, key string, entry *pooled) {
+	defer close(entry.ready)
+	ctx, cancel := context.WithTimeout(ctx, p.options.DialTimeout)
+	defer cancel()
+	entry.client, entry.err = New(ctx, p.name, p.dial, p.options.Health)
+	if entry.err == nil {
+		if !p.options.HealthDisabled {
+			entry.client.Start()
+		}
+		return
+	}
+	p.mu.Lock()
+	if p.entries[key] == entry {
+		delete(p.entries, key)
+	}
+	p.mu.Unlock()
+}
+
+// release drops a reference to entry, closing it when it was removed while
+// in use.
+func (p *Pool) release(entry *pooled) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry.refs--
+	entry.lastUsed = time.Now()
+	if entry.refs == 0 && entry.removed {
+		p.closeEntry(entry)
+	}
+}
+
+// makeRoom evicts the least recently used idle connection when the pool is
+// full; the caller holds p.mu. The pool grows past MaxSize while every
+// connection is in use.
+func (p *Pool) makeRoom() {
+	if p.options.MaxSize <= 0 || len(p.entries) < p.options.MaxSize {
+		return
+	}
+	var oldestKey string
+	var oldest *pooled
+	for key, entry := range p.entries {
+		if entry.refs > 0 {
+			continue
+		}
+		if oldest == nil || entry.lastUsed.Before(oldest.lastUsed) {
+			oldestKey, oldest = key, entry
+		}
+	}
+	if oldest != nil {
+		p.remove(oldestKey)
+	}
+}
+
+// Evict removes connections idle since before now minus the idle timeout and
+// returns how many were removed. Connections in use are kept.
+func (p *Pool) Evict(now time.Time) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	removed := 0
+	for key, entry := range p.entries {
+		if entry.refs == 0 && now.Sub(entry.lastUsed) > p.options.Idle {
+			p.remove(key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// remove drops a connection, closing it once unused; the caller holds p.mu.
+func (p *Pool) remove(key string) {
+	entry := p.entries[key]
+	delete(p.entries, key)
+	entry.removed = true
+	if entry.refs == 0 {
+		p.closeEntry(entry)
+	}
+}
+
+// closeEntry closes the connection of entry once dialed.
+func (p *Pool) closeEntry(entry *pooled) {
+	go func() {
+		<-entry.ready
+		if entry.client != nil {
+			entry.client.Close()
+		}
+	}()
+}
+
+// Start launches background idle eviction.
+func (p *Pool) Start() {
+	p.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(p.options.Idle / 2)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-p.done:
+					return
+				case now := <-ticker.C:
+					p.Evict(now)
+				}
+			}
+		}()
+	})
+}
+
+// Close stops idle eviction and drops every connection; one in use is closed
+// when released.
+func (p *Pool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.done)
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for key := range p.entries {
+			p.remove(key)
+		}
+	})
+}
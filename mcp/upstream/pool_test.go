@@ -0,0 +1,195 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mcpschema "github.com/viant/mcp-protocol/schema"
+	mcpclient "github.com/viant/mcp/client"
+)
+
+// sessionClient is a fake connection identified by the dial sequence.
+type sessionClient struct {
+	mcpclient.Interface
+	id     int
+	closed chan struct{}
+}
+
+func (s *sessionClient) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func TestPool(t *testing.T) {
+	dials := 0
+	var conns []*sessionClient
+	dial := func(ctx context.Context) (mcpclient.Interface, error) {
+		dials++
+		if dials == 1 {
+			return nil, errors.New("connection refused")
+		}
+		conn := &sessionClient{id: dials, closed: make(chan struct{})}
+		conns = append(conns, conn)
+		return conn, nil
+	}
+	pool := NewPool("remote", dial, PoolOptions{Idle: time.Minute, MaxSize: 2, HealthDisabled: true})
+	defer pool.Close()
+	ctx := context.Background()
+
+	// a failed dial is not pooled
+	_, _, err := pool.Acquire(ctx, "a")
+	require.Error(t, err)
+	assert.Equal(t, 0, pool.Len())
+
+	a, release, err := pool.Acquire(ctx, "a")
+	require.NoError(t, err)
+	release()
+	again, release, err := pool.Acquire(ctx, "a")
+	require.NoError(t, err)
+	release()
+	assert.Same(t, a, again, "same key reuses the connection")
+
+	b, release, err := pool.Acquire(ctx, "b")
+	require.NoError(t, err)
+	release()
+	assert.NotSame(t, a, b, "keys are isolated")
+	assert.Equal(t, 2, pool.Len())
+
+	// the pool is full – the least recently used connection (a) makes room
+	_, release, err = pool.Acquire(ctx, "c")
+	require.NoError(t, err)
+	release()
+	assert.Equal(t, 2, pool.Len())
+	select {
+	case <-conns[0].closed:
+	case <-time.After(time.Second):
+		t.Fatal("evicted connection was not closed")
+	}
+
+	assert.Equal(t, 0, pool.Evict(time.Now()))
+	assert.Equal(t, 2, pool.Evict(time.Now().Add(2*time.Minute)))
+	assert.Equal(t, 0, pool.Len())
+}
+
+// TestPool_DialDetached verifies that a caller giving up does not fail the
+// dial shared with other callers and that the dial is time bounded.
+func TestPool_DialDetached(t *testing.T) {
+	gate := make(chan struct{})
+	var dialErr, hasDeadline atomic.Value
+	dial := func(ctx context.Context) (mcpclient.Interface, error) {
+		_, ok := ctx.Deadline()
+		hasDeadline.Store(ok)
+		<-gate
+		if err := ctx.Err(); err != nil {
+			dialErr.Store(err)
+			return nil, err
+		}
+		return &sessionClient{id: 1, closed: make(chan struct{})}, nil
+	}
+	pool := NewPool("remote", dial, PoolOptions{HealthDisabled: true})
+	defer pool.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, _, err := pool.Acquire(ctx, "a")
+		first <- err
+	}()
+	second := make(chan mcpclient.Interface, 1)
+	go func() {
+		client, release, err := pool.Acquire(context.Background(), "a")
+		if err == nil {
+			release()
+		}
+		second <- client
+	}()
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+	close(gate)
+	assert.NotNil(t, <-second, "shared dial completed")
+	assert.Nil(t, dialErr.Load())
+	assert.Equal(t, true, hasDeadline.Load())
+}
+
+func TestPool_InUse(t *testing.T) {
+	var conns []*sessionClient
+	dial := func(ctx context.Context) (mcpclient.Interface, error) {
+		conn := &sessionClient{id: len(conns), closed: make(chan struct{})}
+		conns = append(conns, conn)
+		return conn, nil
+	}
+	pool := NewPool("remote", dial, PoolOptions{Idle: time.Minute, MaxSize: 1, HealthDisabled: true})
+	ctx := context.Background()
+	isClosed := func(conn *sessionClient) bool {
+		select {
+		case <-conn.closed:
+			return true
+		default:
+			return false
+		}
+	}
+
+	_, releaseA, err := pool.Acquire(ctx, "a")
+	require.NoError(t, err)
+	// an in-flight call outlives the idle timeout and a full pool
+	assert.Equal(t, 0, pool.Evict(time.Now().Add(2*time.Minute)))
+	_, releaseB, err := pool.Acquire(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, 2, pool.Len(), "no idle connection to make room")
+	time.Sleep(10 * time.Millisecond)
+	assert.False(t, isClosed(conns[0]), "connection in use kept open")
+
+	// release touches the connection
+	releaseA()
+	assert.Equal(t, 0, pool.Evict(time.Now().Add(30*time.Second)))
+	assert.Equal(t, 1, pool.Evict(time.Now().Add(2*time.Minute)), "only the idle connection is evicted")
+	assert.Eventually(t, func() bool { return isClosed(conns[0]) }, time.Second, time.Millisecond)
+
+	// a connection removed while in use is closed once released
+	pool.Close()
+	time.Sleep(10 * time.Millisecond)
+	assert.False(t, isClosed(conns[1]))
+	releaseB()
+	releaseB()
+	assert.Eventually(t, func() bool { return isClosed(conns[1]) }, time.Second, time.Millisecond)
+}
+
+func TestPool_Reconnect(t *testing.T) {
+	alive := &atomic.Bool{}
+	alive.Store(true)
+	var conns []*pingClient
+	dial := func(ctx context.Context) (mcpclient.Interface, error) {
+		conn := &pingClient{alive: alive}
+		conns = append(conns, conn)
+		return conn, nil
+	}
+	pool := NewPool("remote", dial, PoolOptions{Idle: time.Minute, HealthDisabled: true, Health: Options{InitialBackoff: time.Millisecond}})
+	ctx := context.Background()
+
+	conn, release, err := pool.Acquire(ctx, "a")
+	require.NoError(t, err)
+	release()
+	client, ok := conn.(*Client)
+	require.True(t, ok, "pooled connections are health checked clients")
+
+	// a broken connection is replaced rather than handed out again
+	alive.Store(false)
+	require.NoError(t, client.Check(ctx))
+	alive.Store(true)
+	require.Len(t, conns, 2)
+	assert.True(t, conns[0].closed.Load(), "broken connection closed")
+	again, release, err := pool.Acquire(ctx, "a")
+	require.NoError(t, err)
+	assert.Same(t, conn, again)
+	_, err = again.Ping(ctx, &mcpschema.PingRequestParams{})
+	assert.NoError(t, err)
+	release()
+
+	pool.Close()
+	assert.Eventually(t, conns[1].closed.Load, time.Second, time.Millisecond, "closed with the pool")
+}
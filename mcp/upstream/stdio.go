@@ -0,0 +1,231 @@
+package upstream
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/viant/jsonrpc"
+	"github.com/viant/jsonrpc/transport"
+	"github.com/viant/jsonrpc/transport/client/base"
+	authtransport "github.com/viant/mcp/client/auth/transport"
+)
+
+// stdioRunTimeout bounds a request to a stdio server, as in viant/jsonrpc.
+const stdioRunTimeout = 15 * time.Minute
+
+// exitWait is how long a failed write waits for the server exit status.
+const exitWait = time.Second
+
+// stdioTransport exchanges newline delimited JSON-RPC messages with a server
+// process over its stdin and stdout. Unlike the viant/jsonrpc stdio client,
+// which runs the command line in a gosh shell session it keeps to itself, it
+// owns the shell process, so that Close stops it.
+type stdioTransport struct {
+	name    string
+	base    *base.Client
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	stderr  *tailWriter
+	writeMu sync.Mutex
+	done    chan struct{}
+	exitErr error
+}
+
+// newStdioTransport starts command with args, delivering server messages to
+// handler. As in the viant/jsonrpc stdio client, the command line is command
+// followed by the space separated args and is interpreted by /bin/sh.
+func newStdioTransport(command string, args []string, handler transport.Handler) (*stdioTransport, error) {
+	line := command
+	if len(args) > 0 {
+		line += " " + strings.Join(args, " ")
+	}
+	ret := &stdioTransport{
+		name:   command,
+		cmd:    exec.Command("/bin/sh", "-c", line),
+		stderr: &tailWriter{limit: 4096},
+		done:   make(chan struct{}),
+	}
+	ret.base = &base.Client{
+		RoundTrips: transport.NewRoundTrips(20),
+		RunTimeout: stdioRunTimeout,
+		Transport:  ret,
+		Handler:    handler,
+		Logger:     jsonrpc.DefaultLogger,
+	}
+	ret.cmd.Stderr = ret.stderr
+	setProcessGroup(ret.cmd)
+	var err error
+	if ret.stdin, err = ret.cmd.StdinPipe(); err != nil {
+		return nil, err
+	}
+	stdout, err := ret.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = ret.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %v: %w", command, err)
+	}
+	go ret.read(stdout)
+	return ret, nil
+}
+
+// read dispatches server messages until the process closes its stdout.
+func (t *stdioTransport) read(stdout io.Reader) {
+	ctx := context.WithValue(context.Background(), jsonrpc.SessionKey, "stdio")
+	reader := bufio.NewReader(stdout)
+	for {
+		data, err := reader.ReadBytes('\n')
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			t.base.HandleMessage(ctx, data)
+		}
+		if err != nil {
+			break
+		}
+	}
+	err := t.cmd.Wait()
+	if err == nil {
+		err = errors.New("exited")
+	}
+	if stderr := t.stderr.String(); stderr != "" {
+		err = fmt.Errorf("%w: %s", err, stderr)
+	}
+	t.exitErr = fmt.Errorf("stdio server %v: %w", t.name, err)
+	close(t.done)
+}
+
+// SendData writes a framed message to the server stdin. A failed write
+// usually means the server exited, whose status is the more useful error.
+func (t *stdioTransport) SendData(_ context.Context, data []byte) error {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	_, err := t.stdin.Write(data)
+	if err != nil {
+		timer := time.NewTimer(exitWait)
+		defer timer.Stop()
+		select {
+		case <-t.done:
+			return t.exitErr
+		case <-timer.C:
+		}
+	}
+	return err
+}
+
+// Send sends a request and waits for its response; it fails as soon as the
+// server exits. The caller token, if any, is carried in
+// _meta.authorization.token as there are no HTTP headers on stdio.
+func (t *stdioTransport) Send(ctx context.Context, request *jsonrpc.Request) (*jsonrpc.Response, error) {
+	if err := t.err(); err != nil {
+		return nil, err
+	}
+	if token, _ := ctx.Value(authtransport.ContextAuthTokenKey).(string); token != "" {
+		setAuthMeta(request, token)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-t.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	response, err := t.base.Send(ctx, request)
+	if exitErr := t.err(); err != nil && exitErr != nil {
+		return nil, exitErr
+	}
+	return response, err
+}
+
+// Notify sends a notification.
+func (t *stdioTransport) Notify(ctx context.Context, notification *jsonrpc.Notification) error {
+	if err := t.err(); err != nil {
+		return err
+	}
+	return t.base.Notify(ctx, notification)
+}
+
+func (t *stdioTransport) err() error {
+	select {
+	case <-t.done:
+		return t.exitErr
+	default:
+		return nil
+	}
+}
+
+// Close closes the server stdin and kills the shell with the processes it
+// started.
+func (t *stdioTransport) Close() error {
+	_ = t.stdin.Close()
+	select {
+	case <-t.done:
+		return nil
+	default:
+	}
+	if err := killProcess(t.cmd); err != nil && !errors.Is(err, os.ErrProcessDone) && !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	<-t.done
+	return nil
+}
+
+// setAuthMeta sets params._meta.authorization.token of request.
+func setAuthMeta(request *jsonrpc.Request, token string) {
+	params := map[string]interface{}{}
+	if len(request.Params) > 0 {
+		_ = json.Unmarshal(request.Params, &params)
+	}
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	meta, _ := params["_meta"].(map[string]interface{})
+	if meta == nil {
+		meta = map[string]interface{}{}
+		params["_meta"] = meta
+	}
+	authorization, _ := meta["authorization"].(map[string]interface{})
+	if authorization == nil {
+		authorization = map[string]interface{}{}
+		meta["authorization"] = authorization
+	}
+	authorization["token"] = token
+	if data, err := json.Marshal(params); err == nil {
+		request.Params = data
+	}
+}
+
+// tailWriter keeps the last limit bytes written, e.g. the end of a server
+// stderr for error messages.
+type tailWriter struct {
+	mu    sync.Mutex
+	limit int
+	data  []byte
+}
+
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.data = append(w.data, p...)
+	if extra := len(w.data) - w.limit; extra > 0 {
+		w.data = append(w.data[:0], w.data[extra:]...)
+	}
+	return len(p), nil
+}
+
+func (w *tailWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return strings.TrimSpace(string(w.data))
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package upstream
+
+import "os/exec"
+
+func setProcessGroup(*exec.Cmd) {}
+
+// killProcess kills the shell process of cmd.
+func killProcess(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,39 @@
+package mcp
+
+import (
+	"sort"
+
+	"github.com/viant/fluxor-mcp/mcp/tool"
+	"github.com/viant/fluxor-mcp/mcp/upstream"
+)
+
+// UpstreamStatus reports the connection health of every imported MCP server,
+// ordered by name.
+func (s *Service) UpstreamStatus() []upstream.Status {
+	s.mu.RLock()
+	result := make([]upstream.Status, 0, len(s.upstreams))
+	for _, client := range s.upstreams {
+		result = append(result, client.Status())
+	}
+	s.mu.RUnlock()
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func (s *Service) addUpstream(client *upstream.Client, proxy *tool.Proxy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.upstreams = append(s.upstreams, client)
+	s.proxies = append(s.proxies, proxy)
+}
+
+func (s *Service) closeUpstreams() {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, proxy := range s.proxies {
+		proxy.Close()
+	}
+	for _, client := range s.upstreams {
+		client.Close()
+	}
+}
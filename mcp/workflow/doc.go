@@ -0,0 +1,5 @@
+// Package workflow publishes Fluxor workflow definitions as actions of a
+// single Fluxor service so that every workflow becomes an MCP tool. The input
+// schema of each tool is derived from the workflow's init state and calling
+// the tool runs the whole pipeline as one process.
+package workflow
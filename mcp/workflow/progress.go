@@ -0,0 +1,132 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+	"github.com/viant/fluxor/runtime/execution"
+)
+
+// PollInterval is how often a running process is read for task progress.
+const PollInterval = 100 * time.Millisecond
+
+// processLoader is implemented by runtimes that load process records from
+// their process store.
+type processLoader interface {
+	Process(ctx context.Context, id string) (*execution.Process, error)
+}
+
+// taskStage maps an execution state to a progress stage; states without a
+// stage, e.g. waiting for dependencies, are not reported.
+func taskStage(state execution.TaskState) string {
+	switch state {
+	case "pending", "scheduled":
+		return mcontext.StageScheduled
+	case "running":
+		return mcontext.StageStarted
+	case "completed", "skipped":
+		return mcontext.StageCompleted
+	case "failed":
+		return mcontext.StageFailed
+	}
+	return ""
+}
+
+// taskTracker reports stage changes of the executions of one process.
+type taskTracker struct {
+	workflow string
+	reporter mcontext.Reporter
+	order    []string          // execution IDs in order of appearance
+	tasks    map[string]string // execution ID → task ID
+	stages   map[string]string // execution ID → last reported stage
+}
+
+func (t *taskTracker) report(ctx context.Context, execID, stage string) {
+	if t.stages[execID] == stage {
+		return
+	}
+	t.stages[execID] = stage
+	t.reporter(ctx, stage, fmt.Sprintf("workflow %s task %s %s", t.workflow, t.tasks[execID], stage))
+}
+
+// update reports the stages of the executions on the process stack.
+// Executions that left the stack are finished: failed when their task
+// recorded an error in the process, completed otherwise.
+func (t *taskTracker) update(ctx context.Context, process *execution.Process) {
+	active := map[string]bool{}
+	for _, task := range process.Stack {
+		if task == nil || task.ID == "" {
+			continue
+		}
+		active[task.ID] = true
+		if _, ok := t.tasks[task.ID]; !ok {
+			t.order = append(t.order, task.ID)
+		}
+		t.tasks[task.ID] = task.TaskID
+		if stage := taskStage(task.State); stage != "" {
+			t.report(ctx, task.ID, stage)
+		}
+	}
+	for _, execID := range t.order {
+		if stage := t.stages[execID]; active[execID] || stage == "" || stage == mcontext.StageCompleted || stage == mcontext.StageFailed {
+			continue
+		}
+		if process.Errors[t.tasks[execID]] != "" {
+			t.report(ctx, execID, mcontext.StageFailed)
+		} else {
+			t.report(ctx, execID, mcontext.StageCompleted)
+		}
+	}
+}
+
+// finish reports every unfinished execution as failed when the process failed
+// and as completed otherwise.
+func (t *taskTracker) finish(ctx context.Context, failed bool) {
+	stage := mcontext.StageCompleted
+	if failed {
+		stage = mcontext.StageFailed
+	}
+	for _, execID := range t.order {
+		if last := t.stages[execID]; last != "" && last != mcontext.StageCompleted && last != mcontext.StageFailed {
+			t.report(ctx, execID, stage)
+		}
+	}
+}
+
+// observeProcess reports the stages of the tasks and steps of a running
+// process to reporter, reading the process record every PollInterval. The
+// returned function stops observing and reports the remaining stages; failed
+// tells whether the process failed.
+func observeProcess(ctx context.Context, loader processLoader, processID, workflow string, reporter mcontext.Reporter) func(failed bool) {
+	tracker := &taskTracker{workflow: workflow, reporter: reporter, tasks: map[string]string{}, stages: map[string]string{}}
+	poll := func() {
+		if process, err := loader.Process(ctx, processID); err == nil && process != nil {
+			tracker.update(ctx, process)
+		} // progress is best effort
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				poll()
+			}
+		}
+	}()
+	return func(failed bool) {
+		close(done)
+		<-stopped
+		poll()
+		tracker.finish(ctx, failed)
+	}
+}
@@ -0,0 +1,102 @@
+package workflow
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/fluxor/runtime/execution"
+)
+
+// scriptedLoader returns the scripted processes in turn, then the last one;
+// done is closed once every process was returned.
+type scriptedLoader struct {
+	records []*execution.Process
+	calls   int
+	done    chan struct{}
+}
+
+func (l *scriptedLoader) Process(_ context.Context, id string) (*execution.Process, error) {
+	record := l.records[len(l.records)-1]
+	if l.calls < len(l.records) {
+		record = l.records[l.calls]
+		l.calls++
+		if l.calls == len(l.records) {
+			close(l.done)
+		}
+	}
+	return record, nil
+}
+
+func TestObserveProcess(t *testing.T) {
+	task := func(id, taskID string, state execution.TaskState) *execution.Execution {
+		return &execution.Execution{ID: id, TaskID: taskID, State: state}
+	}
+	process := func(stack ...*execution.Execution) *execution.Process {
+		return &execution.Process{ID: "p1", Stack: stack}
+	}
+	testCases := []struct {
+		description string
+		records     []*execution.Process
+		failed      bool
+		expected    []string
+	}{
+		{
+			description: "tasks and steps",
+			records: []*execution.Process{
+				process(task("1", "first", "running")),
+				process(task("1", "first", "running"), task("2", "first/step", "running")),
+				process(task("3", "second", "pending")),
+				process(task("3", "second", "running")),
+				process(),
+			},
+			expected: []string{
+				"workflow steps task first started",
+				"workflow steps task first/step started",
+				"workflow steps task second scheduled",
+				"workflow steps task first completed",
+				"workflow steps task first/step completed",
+				"workflow steps task second started",
+				"workflow steps task second completed",
+			},
+		},
+		{
+			description: "failed task",
+			records: []*execution.Process{
+				process(task("1", "first", "running")),
+				{ID: "p1", Errors: map[string]string{"first": "boom"}},
+			},
+			failed: true,
+			expected: []string{
+				"workflow steps task first started",
+				"workflow steps task first failed",
+			},
+		},
+		{
+			description: "process finished before the task left the stack",
+			records: []*execution.Process{
+				process(task("1", "first", "running")),
+			},
+			failed: true,
+			expected: []string{
+				"workflow steps task first started",
+				"workflow steps task first failed",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		loader := &scriptedLoader{records: tc.records, done: make(chan struct{})}
+		var mu sync.Mutex
+		var messages []string
+		reporter := func(ctx context.Context, stage, message string) {
+			mu.Lock()
+			defer mu.Unlock()
+			messages = append(messages, message)
+		}
+		stop := observeProcess(context.Background(), loader, "p1", "steps", reporter)
+		<-loader.done
+		stop(tc.failed)
+		assert.EqualValues(t, tc.expected, messages, tc.description)
+	}
+}
@@ -0,0 +1,243 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/viant/afs"
+	"github.com/viant/fluxor"
+	"github.com/viant/fluxor-mcp/internal/conv"
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+	"github.com/viant/fluxor-mcp/mcp/tool/conversion"
+	"github.com/viant/fluxor/model/types"
+	mcpschema "github.com/viant/mcp-protocol/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// ServiceName is the Fluxor service name under which workflows are published;
+// a workflow defined in hello.yaml is exposed as the workflow-hello tool.
+const ServiceName = "workflow"
+
+// DefaultTimeout bounds a workflow run when the caller context has no deadline.
+const DefaultTimeout = 15 * time.Minute
+
+// Output is the result of a workflow run.
+type Output struct {
+	ProcessID string                 `json:"processId,omitempty" description:"identifier of the finished process"`
+	Output    map[string]interface{} `json:"output,omitempty" description:"final workflow output"`
+}
+
+// Service exposes workflow definitions as Fluxor actions.
+type Service struct {
+	runtime   *fluxor.Runtime
+	sigs      types.Signatures
+	executors map[string]types.Executable
+}
+
+func (s *Service) Name() string              { return ServiceName }
+func (s *Service) Methods() types.Signatures { return s.sigs }
+func (s *Service) Method(name string) (types.Executable, error) {
+	if e, ok := s.executors[name]; ok {
+		return e, nil
+	}
+	return nil, types.NewMethodNotFoundError(name)
+}
+
+// New builds the workflow service. Each location may point to a single
+// workflow YAML file or to a directory (local or any afs supported URL) whose
+// *.yaml / *.yml files are all published.
+func New(ctx context.Context, runtime *fluxor.Runtime, locations []string) (*Service, error) {
+	s := &Service{runtime: runtime, executors: map[string]types.Executable{}}
+	fs := afs.New()
+	var urls []string
+	for _, location := range locations {
+		found, err := listDefinitions(ctx, fs, location)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, found...)
+	}
+	for _, URL := range urls {
+		data, err := fs.DownloadWithURL(ctx, URL)
+		if err != nil {
+			return nil, fmt.Errorf("download workflow %q: %w", URL, err)
+		}
+		def, err := parseDefinition(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse workflow %q: %w", URL, err)
+		}
+		name := MethodName(URL)
+		if _, ok := s.executors[name]; ok {
+			return nil, fmt.Errorf("duplicate workflow %q (%s)", name, URL)
+		}
+		inT, err := conversion.TypeFromInputSchema(def.inputSchema())
+		if err != nil {
+			return nil, fmt.Errorf("build input for workflow %q: %w", URL, err)
+		}
+		description := def.Description
+		if description == "" {
+			description = "Run workflow " + path.Base(URL)
+		}
+		s.sigs = append(s.sigs, types.Signature{
+			Name:        name,
+			Description: description,
+			Input:       inT,
+			Output:      reflect.TypeOf(&Output{}),
+		})
+		s.executors[name] = s.run(URL)
+	}
+	return s, nil
+}
+
+// run returns an executable starting the workflow as a new process and
+// waiting for its completion.
+func (s *Service) run(URL string) types.Executable {
+	return func(ctx context.Context, input, output interface{}) error {
+		state, err := conv.ToMap(input)
+		if err != nil {
+			return fmt.Errorf("invalid workflow input: %w", err)
+		}
+		if state == nil {
+			state = map[string]interface{}{}
+		}
+		workflow, err := s.runtime.LoadWorkflow(ctx, URL)
+		if err != nil {
+			return fmt.Errorf("load workflow: %w", err)
+		}
+		process, wait, err := s.runtime.StartProcess(ctx, workflow, state)
+		if err != nil {
+			return fmt.Errorf("start process: %w", err)
+		}
+		stopObserving := func(bool) {}
+		if reporter, ok := mcontext.Progress(ctx); ok {
+			reporter(ctx, mcontext.StageStarted, fmt.Sprintf("workflow %s process %s started", workflow.Name, process.ID))
+			if loader, ok := interface{}(s.runtime).(processLoader); ok {
+				stopObserving = observeProcess(ctx, loader, process.ID, workflow.Name, reporter)
+			}
+		}
+		timeout, err := waitTimeout(ctx)
+		if err != nil {
+			stopObserving(true)
+			return fmt.Errorf("wait for process %s: %w", process.ID, err)
+		}
+		result, err := wait(ctx, timeout)
+		stopObserving(err != nil)
+		if err != nil {
+			return fmt.Errorf("wait for process %s: %w", process.ID, err)
+		}
+		out := &Output{ProcessID: process.ID}
+		if err := conv.Convert(result, &out.Output); err != nil {
+			return fmt.Errorf("convert process %s output: %w", process.ID, err)
+		}
+		if output != nil {
+			return conv.Convert(out, output)
+		}
+		return nil
+	}
+}
+
+// waitTimeout returns how long a run may wait for its process: the time left
+// until the ctx deadline or DefaultTimeout. A passed deadline is an error.
+func waitTimeout(ctx context.Context) (time.Duration, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return DefaultTimeout, nil
+	}
+	timeout := time.Until(deadline)
+	if timeout <= 0 {
+		return 0, context.DeadlineExceeded
+	}
+	return timeout, nil
+}
+
+// MethodName derives the action name from a workflow location: the file name
+// without extension. Dashes are replaced as they separate service and method
+// in tool names.
+func MethodName(URL string) string {
+	name := path.Base(URL)
+	name = strings.TrimSuffix(name, path.Ext(name))
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// listDefinitions expands location into workflow definition URLs.
+func listDefinitions(ctx context.Context, fs afs.Service, location string) ([]string, error) {
+	object, err := fs.Object(ctx, location)
+	if err != nil {
+		return nil, fmt.Errorf("workflow location %q: %w", location, err)
+	}
+	if !object.IsDir() {
+		return []string{object.URL()}, nil
+	}
+	objects, err := fs.List(ctx, location)
+	if err != nil {
+		return nil, fmt.Errorf("list workflows %q: %w", location, err)
+	}
+	var result []string
+	for _, candidate := range objects {
+		if candidate.IsDir() {
+			continue
+		}
+		switch strings.ToLower(path.Ext(candidate.Name())) {
+		case ".yaml", ".yml":
+			result = append(result, candidate.URL())
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// definition captures the parts of a workflow document relevant to its tool
+// signature.
+type definition struct {
+	Description string                 `yaml:"description"`
+	Init        map[string]interface{} `yaml:"init"`
+}
+
+func parseDefinition(data []byte) (*definition, error) {
+	def := &definition{}
+	if err := yaml.Unmarshal(data, def); err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+// inputSchema builds the tool input schema from the declared init state:
+// every entry is a typed, optional parameter defaulting to its declared value.
+func (d *definition) inputSchema() mcpschema.ToolInputSchema {
+	properties := map[string]map[string]interface{}{}
+	for name, value := range d.Init {
+		property := map[string]interface{}{"description": "initial state " + name}
+		if jsonType := schemaType(value); jsonType != "" {
+			property["type"] = jsonType
+		}
+		if value != nil {
+			property["default"] = value
+		}
+		properties[name] = property
+	}
+	return mcpschema.ToolInputSchema{Type: "object", Properties: properties}
+}
+
+// schemaType maps a YAML decoded value to a JSON schema type.
+func schemaType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs"
+)
+
+func TestDefinition_InputSchema(t *testing.T) {
+	testCases := []struct {
+		name     string
+		yaml     string
+		expected map[string]map[string]interface{}
+	}{
+		{
+			name: "undeclared references",
+			yaml: `# uses $HOME
+pipeline:
+  greet:
+    action: printer:print
+    input:
+      message: $name ${HOME}`,
+			expected: map[string]map[string]interface{}{},
+		},
+		{
+			name: "declared init state",
+			yaml: `init:
+  count: 3
+  label: total
+pipeline:
+  show:
+    action: printer:print
+    input:
+      message: ${label} $count $show.output`,
+			expected: map[string]map[string]interface{}{
+				"count": {"description": "initial state count", "type": "integer", "default": 3},
+				"label": {"description": "initial state label", "type": "string", "default": "total"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			def, err := parseDefinition([]byte(tc.yaml))
+			if !assert.NoError(t, err) {
+				return
+			}
+			schema := def.inputSchema()
+			assert.EqualValues(t, "object", schema.Type)
+			assert.EqualValues(t, tc.expected, map[string]map[string]interface{}(schema.Properties))
+		})
+	}
+}
+
+func TestListDefinitions(t *testing.T) {
+	urls, err := listDefinitions(context.Background(), afs.New(), "../../examples")
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.Len(t, urls, 1) {
+		assert.EqualValues(t, "hello", MethodName(urls[0]))
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	bounded, cancelBounded := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelBounded()
+
+	timeout, err := waitTimeout(context.Background())
+	assert.NoError(t, err)
+	assert.EqualValues(t, DefaultTimeout, timeout)
+
+	timeout, err = waitTimeout(bounded)
+	assert.NoError(t, err)
+	assert.True(t, timeout > 0 && timeout <= time.Minute)
+
+	_, err = waitTimeout(expired)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
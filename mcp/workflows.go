@@ -0,0 +1,20 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/viant/fluxor-mcp/mcp/workflow"
+)
+
+// registerWorkflowTools turns configured workflow definitions into actions of
+// the workflow service so that each workflow is exposed as a single tool.
+func (s *Service) registerWorkflowTools(ctx context.Context) error {
+	if len(s.config.Workflows) == 0 {
+		return nil
+	}
+	svc, err := workflow.New(ctx, s.Workflow.Runtime, s.config.Workflows)
+	if err != nil {
+		return err
+	}
+	return s.Workflow.Service.Actions().Register(svc)
+}
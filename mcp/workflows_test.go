@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/fluxor-mcp/mcp/config"
+	mcontext "github.com/viant/fluxor-mcp/mcp/context"
+)
+
+// TestService_WorkflowTaskProgress runs a two task workflow on the Fluxor
+// runtime and verifies that the stages of each task are reported on the
+// caller's progress reporter.
+func TestService_WorkflowTaskProgress(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	definition := `pipeline:
+  first:
+    action: system/exec:execute
+    input:
+      commands: ["sleep 0.5"]
+  second:
+    action: system/exec:execute
+    input:
+      commands: ["sleep 0.5"]
+`
+	if !assert.NoError(t, os.WriteFile(filepath.Join(dir, "steps.yaml"), []byte(definition), 0644)) {
+		return
+	}
+	svc, err := New(ctx, WithConfig(&config.Config{Workflows: []string{dir}}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer svc.Shutdown(ctx)
+
+	var mu sync.Mutex
+	var messages []string
+	callCtx := mcontext.WithProgress(ctx, func(ctx context.Context, stage, message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		messages = append(messages, message)
+	})
+	_, err = svc.ExecuteTool(callCtx, "workflow-steps", nil, time.Minute)
+	if !assert.NoError(t, err) {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, expected := range []string{`task \S*first started`, `task \S*first completed`, `task \S*second started`, `task \S*second completed`} {
+		matched := false
+		for _, message := range messages {
+			if regexp.MustCompile(expected).MatchString(message) {
+				matched = true
+				break
+			}
+		}
+		assert.True(t, matched, "expected progress %q in %v", expected, messages)
+	}
+}